@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"tuiflix/internal/api"
+	"tuiflix/internal/config"
+)
+
+// runLogin handles "tuiflix login realdebrid".
+func runLogin(args []string) int {
+	if len(args) != 1 || args[0] != api.DebridRealDebrid {
+		fmt.Fprintln(os.Stderr, "usage: tuiflix login realdebrid")
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	oauth := api.NewRealDebridOAuth("")
+	code, err := oauth.StartDevice(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tuiflix error: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Open %s and enter the code %s\n", code.VerificationURL, code.UserCode)
+	fmt.Printf("Waiting for approval (expires in %s)...\n", time.Until(code.ExpiresAt).Round(time.Second))
+
+	token, err := oauth.WaitForToken(ctx, code)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tuiflix error: %v\n", err)
+		return 1
+	}
+	if err := config.SaveRealDebridLogin(token); err != nil {
+		fmt.Fprintf(os.Stderr, "tuiflix error: %v\n", err)
+		return 1
+	}
+
+	fmt.Println("Logged in to Real-Debrid")
+	return 0
+}
@@ -3,29 +3,88 @@ package main
 import (
 	"fmt"
 	"os"
-	"strings"
+	"path/filepath"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/joho/godotenv"
 
 	"tuiflix/internal/api"
 	"tuiflix/internal/app"
+	"tuiflix/internal/config"
+	"tuiflix/internal/player"
+	"tuiflix/internal/poster"
+	"tuiflix/internal/store"
 )
 
 func main() {
 	_ = godotenv.Load(".env")
 
-	rdToken := strings.TrimSpace(os.Getenv("REALDEBRID"))
-	client := api.NewClient(rdToken)
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tuiflix: ignoring config: %v\n", err)
+	}
+
+	client, err := api.NewClient(cfg.ClientConfig())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tuiflix error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(client, os.Args[1:]))
+	}
+
+	p, err := player.New(cfg.Player.Backend, cfg.Player.Command)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tuiflix error: %v\n", err)
+		os.Exit(1)
+	}
+
+	st, err := store.Open()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tuiflix: watch history unavailable: %v\n", err)
+	}
+
+	posters := newPosterRenderer(cfg)
 
 	program := tea.NewProgram(
-		app.NewModel(client),
+		app.NewModel(client, p, st, cfg, posters),
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(),
 	)
 
-	if err := program.Start(); err != nil {
+	err = program.Start()
+	_ = posters.Close(os.Stdout)
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "tuiflix error: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+func newPosterRenderer(cfg config.Config) *poster.Renderer {
+	protocol, err := poster.ParseProtocol(cfg.Posters.Protocol, os.Getenv)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tuiflix: posters disabled: %v\n", err)
+	}
+
+	dir, err := config.CacheDir()
+	if err != nil {
+		dir = ""
+	} else {
+		dir = filepath.Join(dir, "posters")
+	}
+	return poster.NewRenderer(protocol, poster.NewCache(dir))
+}
+
+func runCommand(client *api.Client, args []string) int {
+	switch args[0] {
+	case "rd":
+		return runRD(client, args[1:])
+	case "login":
+		return runLogin(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "tuiflix: unknown command %q\n", args[0])
+		fmt.Fprintln(os.Stderr, "usage: tuiflix [rd prune | login realdebrid]")
+		return 2
+	}
+}
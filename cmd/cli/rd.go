@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"tuiflix/internal/api"
+)
+
+// runRD handles the "tuiflix rd ..." subcommands.
+func runRD(client *api.Client, args []string) int {
+	if len(args) == 0 || args[0] != "prune" {
+		fmt.Fprintln(os.Stderr, "usage: tuiflix rd prune [-yes] [-dry-run]")
+		return 2
+	}
+	return runRDPrune(client, args[1:])
+}
+
+func runRDPrune(client *api.Client, args []string) int {
+	flags := flag.NewFlagSet("rd prune", flag.ContinueOnError)
+	yes := flags.Bool("yes", false, "delete without asking for confirmation")
+	dryRun := flags.Bool("dry-run", false, "only list what would be deleted")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if !client.RealDebridEnabled() {
+		fmt.Fprintln(os.Stderr, "tuiflix: rd prune needs a Real-Debrid token")
+		return 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	torrents, err := client.RealDebridTorrents(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tuiflix error: %v\n", err)
+		return 1
+	}
+
+	stale := api.StaleTorrents(torrents, time.Now())
+	if len(stale) == 0 {
+		fmt.Printf("Nothing to prune (%d torrent(s) in the account)\n", len(torrents))
+		return 0
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATUS\tADDED\tNAME")
+	for _, torrent := range stale {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", torrent.ID, torrent.Status, torrent.Added.Local().Format("2006-01-02 15:04"), torrent.Filename)
+	}
+	w.Flush()
+
+	if *dryRun {
+		return 0
+	}
+	if !*yes && !confirm(fmt.Sprintf("Delete %d stale torrent(s)?", len(stale))) {
+		fmt.Println("Aborted")
+		return 0
+	}
+
+	failed := 0
+	for _, torrent := range stale {
+		if err := client.DeleteRealDebridTorrent(ctx, torrent.ID); err != nil {
+			fmt.Fprintf(os.Stderr, "tuiflix: failed to delete %s: %v\n", torrent.ID, err)
+			failed++
+		}
+	}
+	fmt.Printf("Deleted %d torrent(s)\n", len(stale)-failed)
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+func confirm(prompt string) bool {
+	fmt.Printf("%s [y/N] ", prompt)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
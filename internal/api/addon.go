@@ -0,0 +1,316 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	cinemetaBase  = "https://v3-cinemeta.strem.io"
+	torrentioBase = "https://torrentio.strem.fun"
+)
+
+var DefaultAddons = []string{cinemetaBase, torrentioBase}
+
+type Manifest struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Version     string          `json:"version"`
+	Description string          `json:"description"`
+	Types       []string        `json:"types"`
+	IDPrefixes  []string        `json:"idPrefixes"`
+	Resources   []AddonResource `json:"resources"`
+	Catalogs    []Catalog       `json:"catalogs"`
+}
+
+type AddonResource struct {
+	Name       string   `json:"name"`
+	Types      []string `json:"types"`
+	IDPrefixes []string `json:"idPrefixes"`
+}
+
+// UnmarshalJSON accepts both the short form ("stream") and the object form
+// ({"name": "stream", "types": [...]}) allowed by the addon protocol.
+func (r *AddonResource) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*r = AddonResource{Name: name}
+		return nil
+	}
+
+	type plain AddonResource
+	var value plain
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*r = AddonResource(value)
+	return nil
+}
+
+type Catalog struct {
+	Type           string         `json:"type"`
+	ID             string         `json:"id"`
+	Name           string         `json:"name"`
+	Extra          []CatalogExtra `json:"extra"`
+	ExtraSupported []string       `json:"extraSupported"`
+	ExtraRequired  []string       `json:"extraRequired"`
+}
+
+type CatalogExtra struct {
+	Name       string   `json:"name"`
+	Options    []string `json:"options"`
+	IsRequired bool     `json:"isRequired"`
+}
+
+func (c Catalog) SupportsExtra(name string) bool {
+	for _, extra := range c.Extra {
+		if extra.Name == name {
+			return true
+		}
+	}
+	for _, extra := range c.ExtraSupported {
+		if extra == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ExtraOptions returns the values the addon offers for the extra property
+// name, such as the genres of a catalog.
+func (c Catalog) ExtraOptions(name string) []string {
+	for _, extra := range c.Extra {
+		if extra.Name == name {
+			return extra.Options
+		}
+	}
+	return nil
+}
+
+// RequiresOnly reports whether every extra property the catalog requires is
+// name, so it can be listed with just that one set.
+func (c Catalog) RequiresOnly(name string) bool {
+	for _, extra := range c.Extra {
+		if extra.IsRequired && extra.Name != name {
+			return false
+		}
+	}
+	for _, extra := range c.ExtraRequired {
+		if extra != name {
+			return false
+		}
+	}
+	return true
+}
+
+// RequiresExtra reports whether the catalog can only be fetched with some
+// extra property set, such as a search query or a genre.
+func (c Catalog) RequiresExtra() bool {
+	for _, extra := range c.Extra {
+		if extra.IsRequired {
+			return true
+		}
+	}
+	return len(c.ExtraRequired) > 0
+}
+
+// Supports reports whether the addon serves resource for mediaType and id.
+// An empty id skips the prefix check.
+func (m Manifest) Supports(resource string, mediaType string, id string) bool {
+	if resource == "catalog" {
+		for _, catalog := range m.Catalogs {
+			if mediaType == "" || catalog.Type == mediaType {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, r := range m.Resources {
+		if r.Name != resource {
+			continue
+		}
+
+		types := r.Types
+		if len(types) == 0 {
+			types = m.Types
+		}
+		prefixes := r.IDPrefixes
+		if len(prefixes) == 0 {
+			prefixes = m.IDPrefixes
+		}
+
+		if mediaType != "" && len(types) > 0 && !containsString(types, mediaType) {
+			return false
+		}
+		if id != "" && len(prefixes) > 0 && !hasAnyPrefix(id, prefixes) {
+			return false
+		}
+		return true
+	}
+
+	return false
+}
+
+type addonClient struct {
+	base string
+	http *http.Client
+
+	mu         sync.Mutex
+	manifest   *Manifest
+	configPath string
+}
+
+func newAddonClient(rawURL string, httpClient *http.Client) *addonClient {
+	return &addonClient{base: normalizeAddonURL(rawURL), http: httpClient}
+}
+
+func normalizeAddonURL(rawURL string) string {
+	base := strings.TrimSpace(rawURL)
+	if strings.HasPrefix(base, "stremio://") {
+		base = "https://" + strings.TrimPrefix(base, "stremio://")
+	}
+	base = strings.TrimSuffix(base, "/manifest.json")
+	return strings.TrimRight(base, "/")
+}
+
+// fetchManifest returns the addon's manifest, fetching it on first use. The
+// lock is not held during the request, so readers like name do not wait on
+// the network; concurrent first fetches keep whichever result lands first.
+func (a *addonClient) fetchManifest(ctx context.Context) (Manifest, error) {
+	a.mu.Lock()
+	cached := a.manifest
+	a.mu.Unlock()
+	if cached != nil {
+		return *cached, nil
+	}
+
+	var manifest Manifest
+	if err := a.getJSON(ctx, a.base+"/manifest.json", &manifest); err != nil {
+		return Manifest{}, fmt.Errorf("%s: %w", a.base, err)
+	}
+	if manifest.Name == "" {
+		manifest.Name = manifest.ID
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.manifest == nil {
+		a.manifest = &manifest
+	}
+	return *a.manifest, nil
+}
+
+func (a *addonClient) name() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.manifest != nil && a.manifest.Name != "" {
+		return a.manifest.Name
+	}
+	if parsed, err := url.Parse(a.base); err == nil && parsed.Host != "" {
+		return parsed.Host
+	}
+	return a.base
+}
+
+// setConfigPath sets the addon-specific configuration segment inserted
+// between the base URL and resource paths.
+func (a *addonClient) setConfigPath(segment string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.configPath = segment
+}
+
+func (a *addonClient) resourceURL(resource string, mediaType string, id string, extra url.Values) string {
+	a.mu.Lock()
+	base := a.base
+	if a.configPath != "" {
+		base += "/" + a.configPath
+	}
+	a.mu.Unlock()
+
+	endpoint := base + "/" + resource + "/" + url.PathEscape(mediaType) + "/" + url.PathEscape(id)
+	if len(extra) > 0 {
+		parts := make([]string, 0, len(extra))
+		for _, key := range sortedKeys(extra) {
+			parts = append(parts, key+"="+url.PathEscape(extra.Get(key)))
+		}
+		endpoint += "/" + strings.Join(parts, "&")
+	}
+	return endpoint + ".json"
+}
+
+func (a *addonClient) fetchResource(ctx context.Context, resource string, mediaType string, id string, extra url.Values, out any) error {
+	err := a.getJSON(ctx, a.resourceURL(resource, mediaType, id, extra), out)
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		// The config path can hold a debrid token, so the error names the
+		// addon instead of the URL it requested.
+		return fmt.Errorf("%s %s: %w", urlErr.Op, a.base, urlErr.Err)
+	}
+	return err
+}
+
+func (a *addonClient) getJSON(ctx context.Context, endpoint string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", appUserAgent)
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+
+	resp, err := a.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+		return fmt.Errorf("request failed (%d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	if err := decoder.Decode(out); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnyPrefix(value string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(values url.Values) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,224 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAddonManifestDiscovery(t *testing.T) {
+	catalogAddon := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/manifest.json":
+			fmt.Fprint(w, `{"id":"test.catalog","name":"Catalog","types":["movie","series"],
+				"resources":["catalog",{"name":"meta","types":["series"],"idPrefixes":["tt"]}],
+				"catalogs":[
+					{"type":"movie","id":"top","extra":[{"name":"search"},{"name":"skip"}]},
+					{"type":"movie","id":"year","extra":[{"name":"genre","isRequired":true}]},
+					{"type":"series","id":"top","extraSupported":["search"]}
+				]}`)
+		case "/catalog/movie/top/search=the matrix.json":
+			fmt.Fprint(w, `{"metas":[{"id":"tt0133093","name":"The Matrix","year":1999}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer catalogAddon.Close()
+
+	streamAddon := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/manifest.json":
+			fmt.Fprint(w, `{"id":"test.stream","name":"Streams","types":["movie","series"],"idPrefixes":["tt"],"resources":["stream"],"catalogs":[]}`)
+		case "/stream/series/tt0944947:1:2.json":
+			fmt.Fprint(w, `{"streams":[{"name":"A","title":"a","infoHash":"abc","fileIdx":"3"}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer streamAddon.Close()
+
+	client, err := NewClient(Config{Addons: []string{catalogAddon.URL + "/manifest.json", streamAddon.URL}})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	ctx := context.Background()
+
+	manifests, err := client.Addons(ctx)
+	if err != nil {
+		t.Fatalf("Addons failed: %v", err)
+	}
+	if len(manifests) != 2 {
+		t.Fatalf("expected 2 manifests, got %d", len(manifests))
+	}
+	if !manifests[0].Supports("meta", "series", "tt0944947") {
+		t.Fatal("catalog addon should support series meta")
+	}
+	if manifests[0].Supports("meta", "movie", "tt0133093") {
+		t.Fatal("catalog addon should not support movie meta")
+	}
+	if manifests[1].Supports("stream", "series", "kitsu:1") {
+		t.Fatal("stream addon should reject unknown id prefixes")
+	}
+
+	movies, err := client.searchCatalog(ctx, "movie", "the matrix", 0)
+	if err != nil {
+		t.Fatalf("searchCatalog failed: %v", err)
+	}
+	if len(movies) != 1 || movies[0].Year != 1999 {
+		t.Fatalf("unexpected search results: %+v", movies)
+	}
+
+	streams, err := client.FetchStreams(ctx, MediaItem{ID: "tt0944947", Type: "series"}, 1, 2)
+	if err != nil {
+		t.Fatalf("FetchStreams failed: %v", err)
+	}
+	if len(streams) != 1 || streams[0].FileIdx == nil || *streams[0].FileIdx != 3 {
+		t.Fatalf("unexpected streams: %+v", streams)
+	}
+
+	broken := httptest.NewServer(http.NotFoundHandler())
+	defer broken.Close()
+	client, err = NewClient(Config{Addons: []string{streamAddon.URL, broken.URL}})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	streams, err = client.FetchStreams(ctx, MediaItem{ID: "tt0944947", Type: "series"}, 1, 2)
+	var failed SourceErrors
+	if !errors.As(err, &failed) || len(failed) != 1 || failed[0].Source != client.addons[1].name() {
+		t.Fatalf("expected the broken manifest as a source error, got %v", err)
+	}
+	if len(streams) != 1 {
+		t.Fatalf("expected the working addon's streams, got %+v", streams)
+	}
+}
+
+func TestStreamErrorsHideConfigPath(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/manifest.json" {
+			fmt.Fprint(w, `{"id":"test.stream","name":"Streams","types":["movie"],"resources":["stream"],"catalogs":[]}`)
+			return
+		}
+		panic(http.ErrAbortHandler)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Config{Addons: []string{server.URL}})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	client.addons[0].setConfigPath("realdebrid=SECRET-TOKEN")
+
+	_, err = client.FetchStreams(context.Background(), MediaItem{ID: "tt0133093", Type: "movie"}, 0, 0)
+	if err == nil {
+		t.Fatal("expected the aborted stream request to fail")
+	}
+	if strings.Contains(err.Error(), "SECRET-TOKEN") {
+		t.Fatalf("error leaks the debrid token: %v", err)
+	}
+}
+
+func TestMergeStreamsDropsDuplicateTorrents(t *testing.T) {
+	one, two := 1, 2
+	merged := mergeStreams([][]Stream{
+		{{InfoHash: "ABC", FileIdx: &one, Addon: "a"}, {InfoHash: "abc", FileIdx: &two, Addon: "a"}},
+		{{InfoHash: "abc", FileIdx: &one, Addon: "b"}, {URL: "https://example.com/x.mkv", Addon: "b"}},
+	})
+
+	if len(merged) != 3 {
+		t.Fatalf("expected 3 streams, got %d: %+v", len(merged), merged)
+	}
+	if merged[0].Addon != "a" || merged[2].Addon != "b" {
+		t.Fatalf("unexpected merge order: %+v", merged)
+	}
+}
+
+func TestBrowseCatalogs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/manifest.json":
+			fmt.Fprint(w, `{"id":"test.catalog","name":"Cinemeta","types":["movie","series"],"resources":["catalog"],
+				"catalogs":[
+					{"type":"movie","id":"top","name":"Popular","extra":[{"name":"genre","options":["Action","Sci-Fi"]},{"name":"search"},{"name":"skip"}]},
+					{"type":"series","id":"top","name":"Popular","extra":[{"name":"genre","options":["Sci-Fi","Reality-TV"]},{"name":"search"},{"name":"skip"}]},
+					{"type":"movie","id":"year","name":"New","extra":[{"name":"genre","options":["2024","1999"],"isRequired":true}]},
+					{"type":"movie","id":"lookup","name":"Lookup","extra":[{"name":"search","isRequired":true}]}
+				]}`)
+		case "/catalog/movie/top/genre=Sci-Fi.json":
+			fmt.Fprint(w, `{"metas":[{"id":"tt0133093","name":"The Matrix","year":1999}]}`)
+		case "/catalog/series/top/genre=Sci-Fi.json":
+			fmt.Fprint(w, `{"metas":[{"id":"tt0475784","name":"Westworld","year":2016}]}`)
+		case "/catalog/movie/top/genre=Sci-Fi&skip=1.json":
+			fmt.Fprint(w, `{"metas":[{"id":"tt0083658","name":"Blade Runner","year":1982}]}`)
+		case "/catalog/series/top/genre=Reality-TV.json":
+			fmt.Fprint(w, `{"metas":[{"id":"tt0367279","name":"Survivor","year":2000}]}`)
+		case "/catalog/movie/year/genre=2024.json":
+			fmt.Fprint(w, `{"metas":[{"id":"tt15239678","name":"Dune: Part Two","year":2024}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Config{Addons: []string{server.URL}})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	ctx := context.Background()
+
+	catalogs, err := client.BrowseCatalogs(ctx)
+	if err != nil {
+		t.Fatalf("BrowseCatalogs failed: %v", err)
+	}
+	if len(catalogs) != 2 {
+		t.Fatalf("expected top and year catalogs, got %+v", catalogs)
+	}
+	top, year := catalogs[0], catalogs[1]
+	if top.ID != "top" || top.GenreRequired || !top.HasType("series") {
+		t.Fatalf("unexpected top catalog: %+v", top)
+	}
+	if got := fmt.Sprint(top.Genres); got != "[Action Sci-Fi Reality-TV]" {
+		t.Fatalf("unexpected top genres: %s", got)
+	}
+	if year.ID != "year" || !year.GenreRequired || year.HasType("series") {
+		t.Fatalf("unexpected year catalog: %+v", year)
+	}
+
+	movies, shows, err := client.FetchCatalog(ctx, top, "Sci-Fi")
+	if err != nil {
+		t.Fatalf("FetchCatalog failed: %v", err)
+	}
+	if len(movies) != 1 || len(shows) != 1 || shows[0].Year != 2016 {
+		t.Fatalf("unexpected Sci-Fi titles: %+v %+v", movies, shows)
+	}
+
+	page, err := client.FetchCatalogPage(ctx, top, "movie", "Sci-Fi", len(movies))
+	if err != nil {
+		t.Fatalf("FetchCatalogPage failed: %v", err)
+	}
+	if len(page) != 1 || page[0].Name != "Blade Runner" {
+		t.Fatalf("unexpected second page: %+v", page)
+	}
+
+	movies, shows, err = client.FetchCatalog(ctx, top, "Reality-TV")
+	if err != nil {
+		t.Fatalf("FetchCatalog with a series-only genre failed: %v", err)
+	}
+	if len(movies) != 0 || len(shows) != 1 {
+		t.Fatalf("movies should skip a genre they do not offer: %+v %+v", movies, shows)
+	}
+
+	movies, _, err = client.FetchCatalog(ctx, year, "")
+	if err != nil {
+		t.Fatalf("FetchCatalog without a required genre failed: %v", err)
+	}
+	if len(movies) != 1 || movies[0].Year != 2024 {
+		t.Fatalf("expected the first year by default, got %+v", movies)
+	}
+	if page, err := client.FetchCatalogPage(ctx, year, "movie", "2024", 1); err != nil || page != nil {
+		t.Fatalf("a catalog without skip should have no second page, got %+v, %v", page, err)
+	}
+}
@@ -0,0 +1,180 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	allDebridBase  = "https://api.alldebrid.com/v4"
+	allDebridAgent = "tuiflix"
+	allDebridReady = 4
+)
+
+type allDebridService struct {
+	token string
+	http  *http.Client
+}
+
+func newAllDebridService(token string) *allDebridService {
+	return &allDebridService{token: token, http: debridHTTPClient()}
+}
+
+func (a *allDebridService) Name() string {
+	return "AllDebrid"
+}
+
+func (a *allDebridService) Enabled() bool {
+	return a.token != ""
+}
+
+func (a *allDebridService) ResolveMagnet(ctx context.Context, magnet string, fileIdx *int) (string, error) {
+	var upload struct {
+		Magnets []struct {
+			ID    int64 `json:"id"`
+			Error *struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		} `json:"magnets"`
+	}
+	if err := a.call(ctx, "/magnet/upload", url.Values{"magnets[]": {magnet}}, &upload); err != nil {
+		return "", err
+	}
+	if len(upload.Magnets) == 0 {
+		return "", errors.New("alldebrid returned no magnet")
+	}
+	if upload.Magnets[0].Error != nil {
+		return "", errors.New("alldebrid: " + upload.Magnets[0].Error.Message)
+	}
+	id := upload.Magnets[0].ID
+
+	for i := 0; i < 40; i++ {
+		var status struct {
+			Magnets struct {
+				Status     string `json:"status"`
+				StatusCode int    `json:"statusCode"`
+				Links      []struct {
+					Link     string `json:"link"`
+					Filename string `json:"filename"`
+					Size     int64  `json:"size"`
+				} `json:"links"`
+			} `json:"magnets"`
+		}
+		if err := a.call(ctx, "/magnet/status", url.Values{"id": {fmt.Sprint(id)}}, &status); err != nil {
+			return "", err
+		}
+
+		if status.Magnets.StatusCode == allDebridReady && len(status.Magnets.Links) > 0 {
+			files := make([]debridFile, 0, len(status.Magnets.Links))
+			for _, link := range status.Magnets.Links {
+				files = append(files, debridFile{Path: link.Filename, Bytes: link.Size})
+			}
+			return a.UnrestrictLink(ctx, status.Magnets.Links[pickFileIndex(files, fileIdx)].Link)
+		}
+		if status.Magnets.StatusCode > allDebridReady {
+			return "", errors.New("alldebrid: " + status.Magnets.Status)
+		}
+
+		if err := waitPoll(ctx, 1500*time.Millisecond); err != nil {
+			return "", err
+		}
+	}
+
+	return "", ErrTimeout
+}
+
+func (a *allDebridService) UnrestrictLink(ctx context.Context, link string) (string, error) {
+	var payload struct {
+		Link string `json:"link"`
+	}
+	if err := a.call(ctx, "/link/unlock", url.Values{"link": {link}}, &payload); err != nil {
+		return "", err
+	}
+	if payload.Link == "" {
+		return "", errors.New("alldebrid returned empty download link")
+	}
+	return payload.Link, nil
+}
+
+func (a *allDebridService) CheckCache(ctx context.Context, hashes []string) (map[string]bool, error) {
+	cached := map[string]bool{}
+	hashes = uniqueHashes(hashes)
+	if len(hashes) == 0 {
+		return cached, nil
+	}
+
+	var payload struct {
+		Magnets []struct {
+			Hash    string `json:"hash"`
+			Instant bool   `json:"instant"`
+		} `json:"magnets"`
+	}
+	if err := a.call(ctx, "/magnet/instant", url.Values{"magnets[]": hashes}, &payload); err != nil {
+		return cached, err
+	}
+
+	for _, magnet := range payload.Magnets {
+		if magnet.Instant {
+			cached[strings.ToLower(magnet.Hash)] = true
+		}
+	}
+	return cached, nil
+}
+
+// call posts values to an AllDebrid endpoint and decodes the "data" member
+// of the response envelope into out.
+func (a *allDebridService) call(ctx context.Context, route string, values url.Values, out any) error {
+	endpoint := allDebridBase + route + "?agent=" + allDebridAgent
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(values.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := a.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return readDebridError("alldebrid", resp)
+	}
+
+	var envelope struct {
+		Status string          `json:"status"`
+		Data   json.RawMessage `json:"data"`
+		Error  *struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return err
+	}
+	if envelope.Status != "success" {
+		if envelope.Error == nil {
+			return errors.New("alldebrid request failed")
+		}
+		code := envelope.Error.Code
+		switch {
+		case strings.HasPrefix(code, "AUTH_") || code == "MUST_BE_PREMIUM":
+			return fmt.Errorf("alldebrid: %w (%s)", ErrDebridAuth, envelope.Error.Message)
+		case strings.Contains(code, "TOO_MANY") || strings.Contains(code, "LIMIT"):
+			return fmt.Errorf("alldebrid: %w (%s)", ErrDebridQuota, envelope.Error.Message)
+		}
+		return fmt.Errorf("alldebrid %s: %s", code, envelope.Error.Message)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(envelope.Data, out)
+}
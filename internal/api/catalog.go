@@ -0,0 +1,321 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Episode is one video of a series from its meta resource.
+type Episode struct {
+	Season    int
+	Number    int
+	Name      string
+	Overview  string
+	Thumbnail string
+	Released  time.Time
+}
+
+// Aired reports whether the episode was released by now. Episodes without a
+// release date count as aired.
+func (e Episode) Aired(now time.Time) bool {
+	return e.Released.IsZero() || !e.Released.After(now)
+}
+
+// BrowseCatalog is a catalog that can be browsed without a search query,
+// with the movie and series variants of one addon catalog grouped together.
+type BrowseCatalog struct {
+	ID    string
+	Name  string
+	Addon string
+	// Genres holds the genre options of both variants, movie ones first.
+	Genres []string
+	// GenreRequired is set when the catalog cannot be listed without a
+	// genre, like Cinemeta's year catalog whose genres are years.
+	GenreRequired bool
+
+	addon  *addonClient
+	byType map[string]Catalog
+}
+
+// HasType reports whether the catalog lists titles of mediaType.
+func (b BrowseCatalog) HasType(mediaType string) bool {
+	_, ok := b.byType[mediaType]
+	return ok
+}
+
+// browseCatalogs groups the catalogs of manifest that need nothing but an
+// optional or required genre.
+func browseCatalogs(addon *addonClient, manifest Manifest) []BrowseCatalog {
+	var (
+		catalogs []BrowseCatalog
+		index    = map[string]int{}
+	)
+	for _, catalog := range manifest.Catalogs {
+		if catalog.Type != "movie" && catalog.Type != "series" {
+			continue
+		}
+		if !catalog.RequiresOnly("genre") {
+			continue
+		}
+		required := catalog.RequiresExtra()
+		options := catalog.ExtraOptions("genre")
+		if required && len(options) == 0 {
+			continue
+		}
+
+		pos, ok := index[catalog.ID]
+		if !ok {
+			pos = len(catalogs)
+			index[catalog.ID] = pos
+			catalogs = append(catalogs, BrowseCatalog{
+				ID:     catalog.ID,
+				Name:   catalog.Name,
+				Addon:  manifest.Name,
+				addon:  addon,
+				byType: map[string]Catalog{},
+			})
+		}
+
+		browse := &catalogs[pos]
+		if _, seen := browse.byType[catalog.Type]; seen {
+			continue
+		}
+		browse.byType[catalog.Type] = catalog
+		browse.GenreRequired = browse.GenreRequired || required
+		if browse.Name == "" {
+			browse.Name = catalog.Name
+		}
+		for _, option := range options {
+			if !containsString(browse.Genres, option) {
+				browse.Genres = append(browse.Genres, option)
+			}
+		}
+	}
+
+	for idx := range catalogs {
+		if catalogs[idx].Name == "" {
+			catalogs[idx].Name = catalogs[idx].ID
+		}
+	}
+	return catalogs
+}
+
+// fetchBrowseCatalog lists the mediaType titles of catalog for genre. Types
+// that do not offer the genre come back empty instead of failing.
+func fetchBrowseCatalog(ctx context.Context, catalog BrowseCatalog, mediaType string, genre string, skip int) ([]MediaItem, error) {
+	variant, ok := catalog.byType[mediaType]
+	if !ok {
+		return nil, nil
+	}
+
+	options := variant.ExtraOptions("genre")
+	if genre == "" && variant.RequiresExtra() {
+		genre = options[0]
+	}
+	if genre != "" && len(options) > 0 && !containsString(options, genre) {
+		return nil, nil
+	}
+
+	var extra url.Values
+	if genre != "" {
+		extra = url.Values{"genre": {genre}}
+	}
+	extra, ok = pageExtra(variant, extra, skip)
+	if !ok {
+		return nil, nil
+	}
+	return catalog.addon.fetchCatalog(ctx, mediaType, variant.ID, extra)
+}
+
+func (a *addonClient) fetchCatalog(ctx context.Context, mediaType string, catalogID string, extra url.Values) ([]MediaItem, error) {
+	var payload struct {
+		Metas []struct {
+			ID     string          `json:"id"`
+			Name   string          `json:"name"`
+			Type   string          `json:"type"`
+			Year   json.RawMessage `json:"year"`
+			Poster string          `json:"poster"`
+		} `json:"metas"`
+	}
+
+	if err := a.fetchResource(ctx, "catalog", mediaType, catalogID, extra, &payload); err != nil {
+		return nil, err
+	}
+
+	items := make([]MediaItem, 0, len(payload.Metas))
+	for _, raw := range payload.Metas {
+		itemType := raw.Type
+		if itemType == "" {
+			itemType = mediaType
+		}
+
+		item := MediaItem{
+			ID:     raw.ID,
+			Name:   raw.Name,
+			Type:   itemType,
+			Year:   parseYear(raw.Year),
+			Poster: raw.Poster,
+		}
+
+		if item.ID == "" || item.Name == "" {
+			continue
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (a *addonClient) fetchSeriesEpisodes(ctx context.Context, id string) (map[int][]Episode, error) {
+	var payload struct {
+		Meta struct {
+			Videos []struct {
+				Season    int    `json:"season"`
+				Episode   int    `json:"episode"`
+				Number    int    `json:"number"`
+				Name      string `json:"name"`
+				Title     string `json:"title"`
+				Released  string `json:"released"`
+				Overview  string `json:"overview"`
+				Thumbnail string `json:"thumbnail"`
+			} `json:"videos"`
+		} `json:"meta"`
+	}
+
+	if err := a.fetchResource(ctx, "meta", "series", id, nil, &payload); err != nil {
+		return nil, err
+	}
+
+	bySeason := map[int][]Episode{}
+	for _, video := range payload.Meta.Videos {
+		number := video.Episode
+		if number == 0 {
+			number = video.Number
+		}
+		if video.Season < 1 || number < 1 {
+			continue
+		}
+
+		episode := Episode{
+			Season:    video.Season,
+			Number:    number,
+			Name:      strings.TrimSpace(video.Name),
+			Overview:  strings.TrimSpace(video.Overview),
+			Thumbnail: video.Thumbnail,
+		}
+		if episode.Name == "" {
+			episode.Name = strings.TrimSpace(video.Title)
+		}
+		if released, err := time.Parse(time.RFC3339, video.Released); err == nil {
+			episode.Released = released
+		}
+		bySeason[video.Season] = append(bySeason[video.Season], episode)
+	}
+
+	for season, episodes := range bySeason {
+		sort.SliceStable(episodes, func(i, j int) bool {
+			return episodes[i].Number < episodes[j].Number
+		})
+		bySeason[season] = compactEpisodes(episodes)
+	}
+
+	if len(bySeason) == 0 {
+		bySeason[1] = []Episode{{Season: 1, Number: 1}}
+	}
+
+	return bySeason, nil
+}
+
+// pageExtra adds the skip extra for every page after the first. ok is false
+// when the catalog has no further pages because it does not support skip.
+func pageExtra(catalog Catalog, extra url.Values, skip int) (url.Values, bool) {
+	if skip <= 0 {
+		return extra, true
+	}
+	if !catalog.SupportsExtra("skip") {
+		return nil, false
+	}
+	paged := url.Values{"skip": {strconv.Itoa(skip)}}
+	for key, values := range extra {
+		paged[key] = values
+	}
+	return paged, true
+}
+
+// findCatalog picks the first catalog of mediaType that supports all extras.
+// Without extras, catalogs that require one (such as search) are skipped.
+func findCatalog(manifest Manifest, mediaType string, extras ...string) (Catalog, bool) {
+	for _, catalog := range manifest.Catalogs {
+		if catalog.Type != mediaType {
+			continue
+		}
+
+		supported := true
+		for _, extra := range extras {
+			if !catalog.SupportsExtra(extra) {
+				supported = false
+				break
+			}
+		}
+		if !supported {
+			continue
+		}
+
+		if len(extras) == 0 && catalog.RequiresExtra() {
+			continue
+		}
+		return catalog, true
+	}
+	return Catalog{}, false
+}
+
+func parseYear(raw json.RawMessage) int {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return 0
+	}
+
+	if raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err == nil {
+			year, _ := strconv.Atoi(s)
+			return year
+		}
+		return 0
+	}
+
+	var year int
+	if err := json.Unmarshal(raw, &year); err == nil {
+		return year
+	}
+
+	var f float64
+	if err := json.Unmarshal(raw, &f); err == nil {
+		return int(f)
+	}
+
+	return 0
+}
+
+// compactEpisodes drops repeated episode numbers from a sorted season,
+// keeping the first entry of each.
+func compactEpisodes(input []Episode) []Episode {
+	if len(input) == 0 {
+		return input
+	}
+	result := []Episode{input[0]}
+	for i := 1; i < len(input); i++ {
+		if input[i].Number == input[i-1].Number {
+			continue
+		}
+		result = append(result, input[i])
+	}
+	return result
+}
@@ -3,22 +3,26 @@ package api
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
 	"time"
 )
 
 const (
 	defaultHTTPTime = 20 * time.Second
+	streamsDeadline = 15 * time.Second
 	appUserAgent    = "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/122.0.0.0 Safari/537.36"
 )
 
 type MediaItem struct {
-	ID     string
-	Name   string
-	Type   string
-	Year   int
-	Poster string
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Year   int    `json:"year,omitempty"`
+	Poster string `json:"poster,omitempty"`
 }
 
 type Stream struct {
@@ -28,35 +32,232 @@ type Stream struct {
 	InfoHash string
 	FileIdx  *int
 	Sources  []string
+	Addon    string
+	Info     StreamInfo
+	Cached   bool
+}
+
+type Config struct {
+	// DebridProvider is one of DebridProviders; empty means Real-Debrid.
+	DebridProvider string
+	DebridToken    string
+	// Addons lists Stremio addon base or manifest URLs. Empty means
+	// DefaultAddons.
+	Addons    []string
+	Torrentio TorrentioOptions
+	// RealDebridLogin is used instead of DebridToken for Real-Debrid when
+	// the user logged in through the device flow. OnLoginRefresh is called
+	// with every refreshed token so it can be stored.
+	RealDebridLogin *OAuthToken
+	OnLoginRefresh  func(OAuthToken)
 }
 
 type Client struct {
-	cinemeta  *cinemetaService
-	torrentio *torrentioService
-	rd        *realDebridService
+	addons []*addonClient
+	debrid Debrid
+	// rd is set when Real-Debrid is the active provider, for the account
+	// features only it offers.
+	rd *realDebridService
+
+	// mu guards the Torrentio options and the token in their path, which a
+	// login refresh replaces while requests are in flight.
+	mu             sync.Mutex
+	torrentio      TorrentioOptions
+	debridProvider string
+	debridToken    string
 }
 
-func NewClient(rdToken string) *Client {
+func NewClient(cfg Config) (*Client, error) {
 	httpClient := &http.Client{Timeout: defaultHTTPTime}
 
-	return &Client{
-		cinemeta:  newCinemetaService(httpClient),
-		torrentio: newTorrentioService(httpClient),
-		rd:        newRealDebridService(strings.TrimSpace(rdToken)),
+	urls := cfg.Addons
+	if len(urls) == 0 {
+		urls = DefaultAddons
+	}
+
+	addons := make([]*addonClient, 0, len(urls))
+	for _, rawURL := range urls {
+		if strings.TrimSpace(rawURL) == "" {
+			continue
+		}
+		addons = append(addons, newAddonClient(rawURL, httpClient))
+	}
+
+	debrid, err := NewDebrid(cfg.DebridProvider, cfg.DebridToken)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &Client{
+		addons:         addons,
+		debrid:         debrid,
+		debridProvider: CanonicalDebrid(cfg.DebridProvider),
+		debridToken:    strings.TrimSpace(cfg.DebridToken),
+	}
+	if rd, ok := debrid.(*realDebridService); ok {
+		client.rd = rd
+		client.debridProvider = DebridRealDebrid
+		if client.debridToken == "" && cfg.RealDebridLogin != nil {
+			onRefresh := cfg.OnLoginRefresh
+			rd.session = &oauthSession{
+				oauth: NewRealDebridOAuth(""),
+				token: *cfg.RealDebridLogin,
+				onRefresh: func(token OAuthToken) {
+					client.setDebridToken(token.AccessToken)
+					if onRefresh != nil {
+						onRefresh(token)
+					}
+				},
+			}
+			client.debridToken = cfg.RealDebridLogin.AccessToken
+		}
+	}
+	client.SetTorrentioOptions(cfg.Torrentio)
+	return client, nil
+}
+
+// SetTorrentioOptions reconfigures every Torrentio addon in the list. It is
+// safe to call while requests are in flight.
+func (c *Client) SetTorrentioOptions(opts TorrentioOptions) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.torrentio = opts
+	c.applyTorrentioOptions()
+}
+
+// setDebridToken swaps the token in the Torrentio path after a login
+// refresh.
+func (c *Client) setDebridToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.debridToken = token
+	c.applyTorrentioOptions()
+}
+
+// refreshTorrentioToken refreshes an expired Real-Debrid login when
+// Torrentio gets the token in its path, so the addon does not receive a
+// stale one. The session's refresh hook rebuilds the path.
+func (c *Client) refreshTorrentioToken(ctx context.Context) error {
+	if c.rd == nil || c.rd.session == nil {
+		return nil
+	}
+	c.mu.Lock()
+	keyed := c.torrentio.DebridKey
+	c.mu.Unlock()
+	if !keyed {
+		return nil
+	}
+	_, err := c.rd.session.accessToken(ctx)
+	return err
+}
+
+// applyTorrentioOptions sets the Torrentio path on the addons. c.mu must be
+// held.
+func (c *Client) applyTorrentioOptions() {
+	segment := c.torrentio.pathSegment(c.debridProvider, c.debridToken)
+	for _, addon := range c.addons {
+		if isTorrentioURL(addon.base) {
+			addon.setConfigPath(segment)
+		}
 	}
 }
 
+func (c *Client) isAddonURL(link string) bool {
+	for _, addon := range c.addons {
+		if strings.HasPrefix(link, addon.base+"/") {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *Client) RealDebridEnabled() bool {
-	return c.rd.enabled()
+	return c.rd != nil && c.rd.Enabled()
+}
+
+func (c *Client) DebridEnabled() bool {
+	return c.debrid.Enabled()
+}
+
+func (c *Client) DebridName() string {
+	return c.debrid.Name()
+}
+
+// Addons returns the manifests of every configured addon that could be
+// reached.
+func (c *Client) Addons(ctx context.Context) ([]Manifest, error) {
+	manifests := make([]Manifest, 0, len(c.addons))
+	var errs []error
+	for _, addon := range c.addons {
+		manifest, err := addon.fetchManifest(ctx)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		manifests = append(manifests, manifest)
+	}
+	return manifests, errors.Join(errs...)
+}
+
+func (c *Client) addonsFor(ctx context.Context, resource string, mediaType string, id string) ([]*addonClient, error) {
+	var (
+		matches []*addonClient
+		errs    []error
+	)
+	for _, addon := range c.addons {
+		manifest, err := addon.fetchManifest(ctx)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if manifest.Supports(resource, mediaType, id) {
+			matches = append(matches, addon)
+		}
+	}
+
+	if len(matches) == 0 {
+		if err := errors.Join(errs...); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("no addon provides %s for %s", resource, mediaType)
+	}
+	return matches, nil
+}
+
+func (c *Client) catalogAddon(ctx context.Context, mediaType string, extras ...string) (*addonClient, Catalog, error) {
+	addons, err := c.addonsFor(ctx, "catalog", mediaType, "")
+	if err != nil {
+		return nil, Catalog{}, err
+	}
+
+	for _, addon := range addons {
+		manifest, _ := addon.fetchManifest(ctx)
+		if catalog, ok := findCatalog(manifest, mediaType, extras...); ok {
+			return addon, catalog, nil
+		}
+	}
+	return nil, Catalog{}, fmt.Errorf("no %s catalog supports %s", mediaType, strings.Join(extras, ", "))
+}
+
+func (c *Client) fetchDefaultCatalog(ctx context.Context, mediaType string, skip int) ([]MediaItem, error) {
+	addon, catalog, err := c.catalogAddon(ctx, mediaType)
+	if err != nil {
+		return nil, err
+	}
+	extra, ok := pageExtra(catalog, nil, skip)
+	if !ok {
+		return nil, nil
+	}
+	return addon.fetchCatalog(ctx, mediaType, catalog.ID, extra)
 }
 
 func (c *Client) FetchPopular(ctx context.Context) ([]MediaItem, []MediaItem, error) {
-	movies, err := c.cinemeta.fetchCatalog(ctx, "movie", "top")
+	movies, err := c.fetchDefaultCatalog(ctx, "movie", 0)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	shows, err := c.cinemeta.fetchCatalog(ctx, "series", "top")
+	shows, err := c.fetchDefaultCatalog(ctx, "series", 0)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -64,31 +265,272 @@ func (c *Client) FetchPopular(ctx context.Context) ([]MediaItem, []MediaItem, er
 	return movies, shows, nil
 }
 
+// FetchPopularPage lists the popular titles of mediaType after the first
+// skip ones. It returns nothing when the catalog does not page.
+func (c *Client) FetchPopularPage(ctx context.Context, mediaType string, skip int) ([]MediaItem, error) {
+	return c.fetchDefaultCatalog(ctx, mediaType, skip)
+}
+
+// BrowseCatalogs lists the catalogs of every catalog addon that can be
+// browsed by genre or without any filter, in manifest order.
+func (c *Client) BrowseCatalogs(ctx context.Context) ([]BrowseCatalog, error) {
+	addons, err := c.addonsFor(ctx, "catalog", "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	var catalogs []BrowseCatalog
+	for _, addon := range addons {
+		manifest, err := addon.fetchManifest(ctx)
+		if err != nil {
+			continue
+		}
+		catalogs = append(catalogs, browseCatalogs(addon, manifest)...)
+	}
+	if len(catalogs) == 0 {
+		return nil, fmt.Errorf("no addon offers a browsable catalog")
+	}
+	return catalogs, nil
+}
+
+// FetchCatalog lists the movies and series of catalog, filtered by genre
+// unless it is empty. Catalogs that require a genre fall back to their first
+// option.
+func (c *Client) FetchCatalog(ctx context.Context, catalog BrowseCatalog, genre string) ([]MediaItem, []MediaItem, error) {
+	if catalog.addon == nil {
+		return nil, nil, fmt.Errorf("catalog %s has no addon", catalog.ID)
+	}
+
+	movies, err := fetchBrowseCatalog(ctx, catalog, "movie", genre, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	shows, err := fetchBrowseCatalog(ctx, catalog, "series", genre, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return movies, shows, nil
+}
+
+// FetchCatalogPage lists the mediaType titles of catalog for genre after the
+// first skip ones.
+func (c *Client) FetchCatalogPage(ctx context.Context, catalog BrowseCatalog, mediaType string, genre string, skip int) ([]MediaItem, error) {
+	if catalog.addon == nil {
+		return nil, fmt.Errorf("catalog %s has no addon", catalog.ID)
+	}
+	return fetchBrowseCatalog(ctx, catalog, mediaType, genre, skip)
+}
+
 func (c *Client) Search(ctx context.Context, query string) ([]MediaItem, error) {
-	return c.cinemeta.search(ctx, query)
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, nil
+	}
+
+	var (
+		movies []MediaItem
+		shows  []MediaItem
+		errA   error
+		errB   error
+		wg     sync.WaitGroup
+	)
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		movies, errA = c.searchCatalog(ctx, "movie", query, 0)
+	}()
+	go func() {
+		defer wg.Done()
+		shows, errB = c.searchCatalog(ctx, "series", query, 0)
+	}()
+	wg.Wait()
+
+	if errA != nil {
+		return nil, errA
+	}
+	if errB != nil {
+		return nil, errB
+	}
+
+	return append(movies, shows...), nil
+}
+
+// SearchPage lists the mediaType results for query after the first skip
+// ones.
+func (c *Client) SearchPage(ctx context.Context, mediaType string, query string, skip int) ([]MediaItem, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, nil
+	}
+	return c.searchCatalog(ctx, mediaType, query, skip)
+}
+
+func (c *Client) searchCatalog(ctx context.Context, mediaType string, query string, skip int) ([]MediaItem, error) {
+	addon, catalog, err := c.catalogAddon(ctx, mediaType, "search")
+	if err != nil {
+		return nil, err
+	}
+	extra, ok := pageExtra(catalog, url.Values{"search": {query}}, skip)
+	if !ok {
+		return nil, nil
+	}
+	return addon.fetchCatalog(ctx, mediaType, catalog.ID, extra)
 }
 
-func (c *Client) FetchSeriesEpisodes(ctx context.Context, id string) (map[int][]int, error) {
-	return c.cinemeta.fetchSeriesEpisodes(ctx, id)
+func (c *Client) FetchSeriesEpisodes(ctx context.Context, id string) (map[int][]Episode, error) {
+	addons, err := c.addonsFor(ctx, "meta", "series", id)
+	if err != nil {
+		return nil, err
+	}
+	return addons[0].fetchSeriesEpisodes(ctx, id)
 }
 
 func (c *Client) FetchStreams(ctx context.Context, item MediaItem, season int, episode int) ([]Stream, error) {
-	return c.torrentio.fetchStreams(ctx, item, season, episode)
+	id, err := streamID(item, season, episode)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, streamsDeadline)
+	defer cancel()
+
+	if err := c.refreshTorrentioToken(ctx); err != nil {
+		return nil, err
+	}
+
+	// Each addon resolves its own manifest, so one slow or broken addon
+	// neither holds up the others nor hides its failure behind their
+	// results.
+	results := make([][]Stream, len(c.addons))
+	errs := make([]error, len(c.addons))
+	supported := make([]bool, len(c.addons))
+	var wg sync.WaitGroup
+	for i, addon := range c.addons {
+		wg.Add(1)
+		go func(i int, addon *addonClient) {
+			defer wg.Done()
+			manifest, err := addon.fetchManifest(ctx)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			if supported[i] = manifest.Supports("stream", item.Type, id); supported[i] {
+				results[i], errs[i] = addon.fetchStreams(ctx, item.Type, id)
+			}
+		}(i, addon)
+	}
+	wg.Wait()
+
+	var (
+		failed  SourceErrors
+		sources int
+	)
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, SourceError{Source: c.addons[i].name(), Err: err})
+		}
+		if err != nil || supported[i] {
+			sources++
+		}
+	}
+
+	if sources == 0 {
+		return nil, fmt.Errorf("no addon provides stream for %s", item.Type)
+	}
+	streams := mergeStreams(results)
+	if len(failed) == 0 {
+		return streams, nil
+	}
+	if len(failed) == sources {
+		return nil, failed
+	}
+	return streams, failed
 }
 
+// CheckCached asks the debrid provider which torrents in streams are cached
+// and returns a copy with Cached set on them.
+func (c *Client) CheckCached(ctx context.Context, streams []Stream) ([]Stream, error) {
+	if !c.debrid.Enabled() {
+		return streams, nil
+	}
+
+	hashes := make([]string, 0, len(streams))
+	for _, stream := range streams {
+		if stream.InfoHash != "" && !stream.Cached {
+			hashes = append(hashes, stream.InfoHash)
+		}
+	}
+	if len(hashes) == 0 {
+		return streams, nil
+	}
+
+	cached, err := c.debrid.CheckCache(ctx, hashes)
+	out := append([]Stream(nil), streams...)
+	for idx := range out {
+		if cached[strings.ToLower(out[idx].InfoHash)] {
+			out[idx].Cached = true
+		}
+	}
+	return out, err
+}
+
+// ResolvePlayableURL turns stream into a link the player can open, going
+// through the debrid service when one is configured. Debrid failures match
+// ErrDebrid; callers that want the raw link can use DirectURL.
 func (c *Client) ResolvePlayableURL(ctx context.Context, stream Stream) (string, error) {
 	if stream.URL != "" && strings.HasPrefix(strings.ToLower(stream.URL), "http") {
-		if !c.rd.enabled() {
+		// Addon URLs (such as Torrentio's debrid resolver) are already playable.
+		if !c.debrid.Enabled() || c.isAddonURL(stream.URL) {
 			return stream.URL, nil
 		}
 
-		link, err := c.rd.unrestrictLink(ctx, stream.URL)
+		link, err := c.debrid.UnrestrictLink(ctx, stream.URL)
 		if err != nil {
-			return stream.URL, nil
+			return "", debridFailure(ctx, err)
 		}
 		return link, nil
 	}
 
+	magnet, err := DirectURL(stream)
+	if err != nil {
+		return "", err
+	}
+
+	if !c.debrid.Enabled() {
+		return magnet, nil
+	}
+
+	link, err := c.debrid.ResolveMagnet(ctx, magnet, stream.FileIdx)
+	if err != nil {
+		return "", debridFailure(ctx, err)
+	}
+
+	return link, nil
+}
+
+// UnrestrictLink turns a hoster link, such as one from a debrid torrent,
+// into a direct download link.
+func (c *Client) UnrestrictLink(ctx context.Context, link string) (string, error) {
+	if !c.debrid.Enabled() {
+		return "", fmt.Errorf("%s is not configured", c.debrid.Name())
+	}
+	link, err := c.debrid.UnrestrictLink(ctx, link)
+	if err != nil {
+		return "", debridFailure(ctx, err)
+	}
+	return link, nil
+}
+
+// DirectURL returns the stream's own link without any debrid resolution:
+// the HTTP URL, or a magnet built from its info hash and trackers.
+func DirectURL(stream Stream) (string, error) {
+	if stream.URL != "" && strings.HasPrefix(strings.ToLower(stream.URL), "http") {
+		return stream.URL, nil
+	}
+
 	magnet := stream.URL
 	if !strings.HasPrefix(strings.ToLower(magnet), "magnet:") {
 		magnet = buildMagnet(stream)
@@ -97,15 +539,12 @@ func (c *Client) ResolvePlayableURL(ctx context.Context, stream Stream) (string,
 	if magnet == "" {
 		return "", errors.New("stream does not include a playable URL")
 	}
+	return magnet, nil
+}
 
-	if !c.rd.enabled() {
-		return magnet, nil
+func debridFailure(ctx context.Context, err error) error {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("%w: %v", ErrTimeout, err)
 	}
-
-	link, err := c.rd.resolveMagnet(ctx, magnet, stream.FileIdx)
-	if err != nil {
-		return magnet, nil
-	}
-
-	return link, nil
+	return debridError{err: err}
 }
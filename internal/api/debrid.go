@@ -0,0 +1,186 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	DebridRealDebrid = "realdebrid"
+	DebridAllDebrid  = "alldebrid"
+	DebridPremiumize = "premiumize"
+	DebridTorBox     = "torbox"
+)
+
+// DebridProviders lists the supported providers in the order they are
+// auto-detected from API keys.
+var DebridProviders = []string{DebridRealDebrid, DebridAllDebrid, DebridPremiumize, DebridTorBox}
+
+// debridAliases are the short provider names accepted in the config.
+var debridAliases = map[string]string{
+	"rd": DebridRealDebrid,
+	"ad": DebridAllDebrid,
+	"pm": DebridPremiumize,
+	"tb": DebridTorBox,
+}
+
+// CanonicalDebrid normalizes a configured provider name, turning aliases
+// like "rd" into the full name used for keys and Torrentio paths.
+func CanonicalDebrid(provider string) string {
+	provider = strings.ToLower(strings.TrimSpace(provider))
+	if canonical, ok := debridAliases[provider]; ok {
+		return canonical
+	}
+	return provider
+}
+
+// Debrid turns torrents and hoster links into direct HTTP links through a
+// paid debrid service.
+type Debrid interface {
+	Name() string
+	Enabled() bool
+	ResolveMagnet(ctx context.Context, magnet string, fileIdx *int) (string, error)
+	UnrestrictLink(ctx context.Context, link string) (string, error)
+	// CheckCache reports which of the given info hashes (lowercase) can be
+	// streamed immediately.
+	CheckCache(ctx context.Context, hashes []string) (map[string]bool, error)
+}
+
+// ResolveProgress is a snapshot of a torrent the debrid service is still
+// fetching. Percent runs from 0 to 100 and Speed is in bytes per second.
+type ResolveProgress struct {
+	Status  string
+	Percent float64
+	Speed   int64
+	Seeders int
+}
+
+type progressKey struct{}
+
+// WithProgress returns a context that makes ResolvePlayableURL report every
+// status poll of a debrid resolve to fn.
+func WithProgress(ctx context.Context, fn func(ResolveProgress)) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+func reportProgress(ctx context.Context, progress ResolveProgress) {
+	if fn, ok := ctx.Value(progressKey{}).(func(ResolveProgress)); ok && fn != nil {
+		fn(progress)
+	}
+}
+
+func NewDebrid(provider string, token string) (Debrid, error) {
+	token = strings.TrimSpace(token)
+	switch CanonicalDebrid(provider) {
+	case "", DebridRealDebrid:
+		return newRealDebridService(token), nil
+	case DebridAllDebrid:
+		return newAllDebridService(token), nil
+	case DebridPremiumize:
+		return newPremiumizeService(token), nil
+	case DebridTorBox:
+		return newTorBoxService(token), nil
+	default:
+		return nil, fmt.Errorf("unknown debrid provider: %s", provider)
+	}
+}
+
+func debridHTTPClient() *http.Client {
+	return &http.Client{Timeout: 45 * time.Second}
+}
+
+type debridFile struct {
+	Path  string
+	Bytes int64
+}
+
+// pickFileIndex returns the position of the file to stream: fileIdx when the
+// addon provided a valid one, otherwise the largest video file.
+func pickFileIndex(files []debridFile, fileIdx *int) int {
+	if len(files) == 0 {
+		return -1
+	}
+
+	if fileIdx != nil {
+		idx := *fileIdx
+		if idx >= 0 && idx < len(files) {
+			return idx
+		}
+	}
+
+	best := -1
+	bestBytes := int64(-1)
+	for idx, file := range files {
+		if !isLikelyVideo(file.Path) {
+			continue
+		}
+		if file.Bytes > bestBytes {
+			best = idx
+			bestBytes = file.Bytes
+		}
+	}
+
+	if best >= 0 {
+		return best
+	}
+
+	return 0
+}
+
+func readDebridError(provider string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+	message := strings.TrimSpace(string(body))
+
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%s: %w (%s)", provider, ErrDebridAuth, message)
+	case http.StatusTooManyRequests, http.StatusPaymentRequired:
+		return fmt.Errorf("%s: %w (%s)", provider, ErrDebridQuota, message)
+	}
+	return fmt.Errorf("%s request failed (%d): %s", provider, resp.StatusCode, message)
+}
+
+func waitPoll(ctx context.Context, interval time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(interval):
+		return nil
+	}
+}
+
+func infoHashFromMagnet(magnet string) string {
+	const marker = "urn:btih:"
+	idx := strings.Index(strings.ToLower(magnet), marker)
+	if idx < 0 {
+		return ""
+	}
+	hash := magnet[idx+len(marker):]
+	if end := strings.IndexAny(hash, "&"); end >= 0 {
+		hash = hash[:end]
+	}
+	return strings.ToLower(hash)
+}
+
+func uniqueHashes(hashes []string) []string {
+	seen := map[string]struct{}{}
+	out := make([]string, 0, len(hashes))
+	for _, hash := range hashes {
+		hash = strings.ToLower(strings.TrimSpace(hash))
+		if hash == "" {
+			continue
+		}
+		if _, ok := seen[hash]; ok {
+			continue
+		}
+		seen[hash] = struct{}{}
+		out = append(out, hash)
+	}
+	sort.Strings(out)
+	return out
+}
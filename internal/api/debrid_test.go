@@ -0,0 +1,50 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDebridAliases(t *testing.T) {
+	for alias, want := range map[string]string{"rd": DebridRealDebrid, " AD ": DebridAllDebrid, "pm": DebridPremiumize, "TorBox": DebridTorBox} {
+		if got := CanonicalDebrid(alias); got != want {
+			t.Errorf("CanonicalDebrid(%q) = %q, want %q", alias, got, want)
+		}
+	}
+
+	client, err := NewClient(Config{
+		Addons:         []string{"https://torrentio.strem.fun"},
+		DebridProvider: "ad",
+		DebridToken:    "KEY",
+		Torrentio:      TorrentioOptions{DebridKey: true},
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	if name := client.DebridName(); name != "AllDebrid" {
+		t.Fatalf("expected AllDebrid for the ad alias, got %s", name)
+	}
+	if path := client.addons[0].configPath; path != "alldebrid=KEY" {
+		t.Fatalf("expected the canonical provider in the Torrentio path, got %q", path)
+	}
+}
+
+func TestDebridFailure(t *testing.T) {
+	plain := errors.New("torrent is virus infected")
+	for _, err := range []error{plain, ErrRDAuth, context.DeadlineExceeded} {
+		got := debridFailure(context.Background(), err)
+		if !errors.Is(got, ErrDebrid) {
+			t.Errorf("expected %v to match ErrDebrid", err)
+		}
+		if err != context.DeadlineExceeded && !errors.Is(got, err) {
+			t.Errorf("expected %v to still match itself", err)
+		}
+	}
+	if got := debridFailure(context.Background(), plain); got.Error() != plain.Error() {
+		t.Errorf("expected the message to stay %q, got %q", plain, got)
+	}
+	if got := debridFailure(context.Background(), context.DeadlineExceeded); !errors.Is(got, ErrTimeout) {
+		t.Errorf("expected deadlines to match ErrTimeout, got %v", got)
+	}
+}
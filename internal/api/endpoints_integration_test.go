@@ -12,7 +12,10 @@ import (
 func TestLiveCinemetaAndTorrentioEndpoints(t *testing.T) {
 	requireLiveTests(t)
 
-	client := NewClient(readRealDebridToken())
+	client, err := NewClient(Config{DebridToken: readRealDebridToken()})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -57,7 +60,10 @@ func TestLiveRealDebridEndpoint(t *testing.T) {
 		t.Skip("REALDEBRID token not set")
 	}
 
-	client := NewClient(token)
+	client, err := NewClient(Config{DebridProvider: DebridRealDebrid, DebridToken: token})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrDebrid matches every error the debrid service returns while
+	// resolving a stream.
+	ErrDebrid      = errors.New("debrid service failed")
+	ErrNotCached   = errors.New("torrent is not cached on the debrid service")
+	ErrTimeout     = errors.New("timed out waiting for the debrid service")
+	ErrDebridAuth  = errors.New("debrid service rejected the API key")
+	ErrDebridQuota = errors.New("debrid service limit reached")
+
+	// ErrRDAuth and ErrRDQuota are the Real-Debrid flavours; they match
+	// ErrDebridAuth and ErrDebridQuota with errors.Is.
+	ErrRDAuth  = fmt.Errorf("real-debrid: %w", ErrDebridAuth)
+	ErrRDQuota = fmt.Errorf("real-debrid: %w", ErrDebridQuota)
+
+	// errRDDisabled is returned for endpoints Real-Debrid has switched off,
+	// such as /torrents/instantAvailability.
+	errRDDisabled = errors.New("real-debrid: endpoint disabled")
+)
+
+// debridError marks err as coming from the debrid service without changing
+// its message.
+type debridError struct {
+	err error
+}
+
+func (e debridError) Error() string {
+	return e.err.Error()
+}
+
+func (e debridError) Unwrap() []error {
+	return []error{ErrDebrid, e.err}
+}
+
+// realDebridError maps Real-Debrid's error codes onto the typed errors.
+// See https://api.real-debrid.com/#api_error_codes.
+func realDebridError(status int, code int, message string) error {
+	switch {
+	case status == 401 || code == 8 || code == 9 || code == 14 || code == 22:
+		return fmt.Errorf("%w (%s)", ErrRDAuth, message)
+	case code == 37:
+		return fmt.Errorf("%w (%s)", errRDDisabled, message)
+	case status == 429 || code == 21 || code == 23 || code == 34 || code == 36:
+		return fmt.Errorf("%w (%s)", ErrRDQuota, message)
+	default:
+		return fmt.Errorf("real-debrid request failed (%d): %s", status, message)
+	}
+}
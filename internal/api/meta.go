@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// MediaMeta is the full description of a title from an addon's meta
+// resource.
+type MediaMeta struct {
+	MediaItem
+	Description string
+	Genres      []string
+	Runtime     string
+	IMDbRating  string
+	Cast        []string
+	Director    []string
+	ReleaseInfo string
+	Released    time.Time
+	Trailers    []Trailer
+	Background  string
+}
+
+type Trailer struct {
+	Title     string
+	YouTubeID string
+}
+
+func (t Trailer) URL() string {
+	return "https://www.youtube.com/watch?v=" + t.YouTubeID
+}
+
+type metaLink struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+}
+
+type metaPayload struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Type        string          `json:"type"`
+	Year        json.RawMessage `json:"year"`
+	Poster      string          `json:"poster"`
+	Description string          `json:"description"`
+	Genres      []string        `json:"genres"`
+	Genre       []string        `json:"genre"`
+	Runtime     string          `json:"runtime"`
+	IMDbRating  json.RawMessage `json:"imdbRating"`
+	Cast        []string        `json:"cast"`
+	Director    []string        `json:"director"`
+	ReleaseInfo string          `json:"releaseInfo"`
+	Released    string          `json:"released"`
+	Background  string          `json:"background"`
+	Links       []metaLink      `json:"links"`
+	Trailers    []struct {
+		Source string `json:"source"`
+		Type   string `json:"type"`
+	} `json:"trailers"`
+	TrailerStreams []struct {
+		Title string `json:"title"`
+		YtID  string `json:"ytId"`
+	} `json:"trailerStreams"`
+}
+
+// FetchMeta loads the details of item from the first addon that provides
+// meta for it.
+func (c *Client) FetchMeta(ctx context.Context, item MediaItem) (MediaMeta, error) {
+	addons, err := c.addonsFor(ctx, "meta", item.Type, item.ID)
+	if err != nil {
+		return MediaMeta{}, err
+	}
+	return addons[0].fetchMeta(ctx, item)
+}
+
+func (a *addonClient) fetchMeta(ctx context.Context, item MediaItem) (MediaMeta, error) {
+	var payload struct {
+		Meta metaPayload `json:"meta"`
+	}
+	if err := a.fetchResource(ctx, "meta", item.Type, item.ID, nil, &payload); err != nil {
+		return MediaMeta{}, err
+	}
+	return payload.Meta.toMediaMeta(item), nil
+}
+
+func (p metaPayload) toMediaMeta(item MediaItem) MediaMeta {
+	meta := MediaMeta{
+		MediaItem:   item,
+		Description: strings.TrimSpace(p.Description),
+		Genres:      p.Genres,
+		Runtime:     p.Runtime,
+		Cast:        p.Cast,
+		Director:    p.Director,
+		ReleaseInfo: p.ReleaseInfo,
+		Background:  p.Background,
+	}
+	if p.Name != "" {
+		meta.Name = p.Name
+	}
+	if year := parseYear(p.Year); year > 0 {
+		meta.Year = year
+	}
+	if p.Poster != "" {
+		meta.Poster = p.Poster
+	}
+	if len(meta.Genres) == 0 {
+		meta.Genres = p.Genre
+	}
+	if rating := strings.Trim(string(p.IMDbRating), `"`); rating != "null" {
+		meta.IMDbRating = rating
+	}
+	if released, err := time.Parse(time.RFC3339, p.Released); err == nil {
+		meta.Released = released
+	}
+
+	// Newer Cinemeta responses move people and genres into links.
+	for _, link := range p.Links {
+		switch strings.ToLower(link.Category) {
+		case "cast":
+			if len(p.Cast) == 0 {
+				meta.Cast = append(meta.Cast, link.Name)
+			}
+		case "directors":
+			if len(p.Director) == 0 {
+				meta.Director = append(meta.Director, link.Name)
+			}
+		case "genres":
+			if len(p.Genres) == 0 && len(p.Genre) == 0 {
+				meta.Genres = append(meta.Genres, link.Name)
+			}
+		}
+	}
+
+	for _, trailer := range p.TrailerStreams {
+		if trailer.YtID != "" {
+			meta.Trailers = append(meta.Trailers, Trailer{Title: trailer.Title, YouTubeID: trailer.YtID})
+		}
+	}
+	if len(meta.Trailers) == 0 {
+		for _, trailer := range p.Trailers {
+			if trailer.Source != "" {
+				meta.Trailers = append(meta.Trailers, Trailer{Title: trailer.Type, YouTubeID: trailer.Source})
+			}
+		}
+	}
+	return meta
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFetchMeta(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/manifest.json":
+			fmt.Fprint(w, `{"id":"test.meta","name":"Meta","types":["movie"],"idPrefixes":["tt"],"resources":["meta"],"catalogs":[]}`)
+		case "/meta/movie/tt0133093.json":
+			fmt.Fprint(w, `{"meta":{"id":"tt0133093","name":"The Matrix","releaseInfo":"1999","runtime":"136 min",
+				"imdbRating":"8.7","released":"1999-03-31T00:00:00.000Z","description":" A hacker learns the truth. ",
+				"links":[{"name":"Action","category":"Genres"},{"name":"Keanu Reeves","category":"Cast"},
+					{"name":"Lana Wachowski","category":"Directors"},{"name":"8.7","category":"imdb"}],
+				"trailerStreams":[{"title":"The Matrix","ytId":"vKQi3bBA1y8"}]}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Config{Addons: []string{server.URL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	meta, err := client.FetchMeta(context.Background(), MediaItem{ID: "tt0133093", Name: "Matrix", Type: "movie"})
+	if err != nil {
+		t.Fatalf("FetchMeta: %v", err)
+	}
+
+	if meta.Name != "The Matrix" || meta.Description != "A hacker learns the truth." || meta.IMDbRating != "8.7" || meta.Runtime != "136 min" {
+		t.Fatalf("unexpected meta: %+v", meta)
+	}
+	if !reflect.DeepEqual(meta.Genres, []string{"Action"}) || !reflect.DeepEqual(meta.Cast, []string{"Keanu Reeves"}) || !reflect.DeepEqual(meta.Director, []string{"Lana Wachowski"}) {
+		t.Fatalf("people and genres not taken from links: %+v", meta)
+	}
+	if meta.Released.Year() != 1999 || len(meta.Trailers) != 1 || meta.Trailers[0].URL() != "https://www.youtube.com/watch?v=vKQi3bBA1y8" {
+		t.Fatalf("unexpected release or trailers: %v %+v", meta.Released, meta.Trailers)
+	}
+}
+
+func TestFetchSeriesEpisodes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/manifest.json":
+			fmt.Fprint(w, `{"id":"test.meta","name":"Meta","types":["series"],"idPrefixes":["tt"],"resources":["meta"],"catalogs":[]}`)
+		case "/meta/series/tt0944947.json":
+			fmt.Fprint(w, `{"meta":{"id":"tt0944947","videos":[
+				{"season":1,"episode":2,"name":"The Kingsroad","released":"2011-04-24T00:00:00.000Z"},
+				{"season":1,"episode":1,"name":"Winter Is Coming","released":"2011-04-17T00:00:00.000Z","overview":" Eddard is asked to serve. ","thumbnail":"https://example.com/1.jpg"},
+				{"season":1,"episode":1,"name":"Winter Is Coming (duplicate)"},
+				{"season":0,"episode":1,"name":"Special"},
+				{"season":9,"number":1,"title":"Next Year","released":"2099-01-01T00:00:00.000Z"}]}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Config{Addons: []string{server.URL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bySeason, err := client.FetchSeriesEpisodes(context.Background(), "tt0944947")
+	if err != nil {
+		t.Fatalf("FetchSeriesEpisodes: %v", err)
+	}
+
+	if len(bySeason) != 2 || len(bySeason[1]) != 2 || len(bySeason[9]) != 1 {
+		t.Fatalf("unexpected seasons: %+v", bySeason)
+	}
+	first := bySeason[1][0]
+	if first.Number != 1 || first.Name != "Winter Is Coming" || first.Overview != "Eddard is asked to serve." || first.Thumbnail == "" || first.Released.Day() != 17 {
+		t.Fatalf("unexpected first episode: %+v", first)
+	}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if !first.Aired(now) || bySeason[9][0].Aired(now) || bySeason[9][0].Name != "Next Year" {
+		t.Fatalf("unexpected aired state: %+v", bySeason[9][0])
+	}
+}
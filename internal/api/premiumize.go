@@ -0,0 +1,176 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const premiumizeBase = "https://www.premiumize.me/api"
+
+type premiumizeService struct {
+	token string
+	http  *http.Client
+}
+
+func newPremiumizeService(token string) *premiumizeService {
+	return &premiumizeService{token: token, http: debridHTTPClient()}
+}
+
+func (p *premiumizeService) Name() string {
+	return "Premiumize"
+}
+
+func (p *premiumizeService) Enabled() bool {
+	return p.token != ""
+}
+
+func (p *premiumizeService) ResolveMagnet(ctx context.Context, magnet string, fileIdx *int) (string, error) {
+	if link, err := p.directLink(ctx, magnet, fileIdx); err == nil {
+		return link, nil
+	}
+
+	var transfer struct {
+		ID string `json:"id"`
+	}
+	if err := p.call(ctx, http.MethodPost, "/transfer/create", url.Values{"src": {magnet}}, &transfer); err != nil {
+		return "", err
+	}
+
+	for i := 0; i < 40; i++ {
+		var list struct {
+			Transfers []struct {
+				ID      string `json:"id"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			} `json:"transfers"`
+		}
+		if err := p.call(ctx, http.MethodGet, "/transfer/list", nil, &list); err != nil {
+			return "", err
+		}
+
+		for _, item := range list.Transfers {
+			if item.ID != transfer.ID {
+				continue
+			}
+			switch item.Status {
+			case "finished", "seeding":
+				return p.directLink(ctx, magnet, fileIdx)
+			case "error", "deleted", "banned", "timeout":
+				return "", errors.New("premiumize: " + strings.TrimSpace(item.Status+" "+item.Message))
+			}
+		}
+
+		if err := waitPoll(ctx, 1500*time.Millisecond); err != nil {
+			return "", err
+		}
+	}
+
+	return "", ErrTimeout
+}
+
+func (p *premiumizeService) UnrestrictLink(ctx context.Context, link string) (string, error) {
+	return p.directLink(ctx, link, nil)
+}
+
+func (p *premiumizeService) CheckCache(ctx context.Context, hashes []string) (map[string]bool, error) {
+	cached := map[string]bool{}
+	hashes = uniqueHashes(hashes)
+	if len(hashes) == 0 {
+		return cached, nil
+	}
+
+	var payload struct {
+		Response []bool `json:"response"`
+	}
+	if err := p.call(ctx, http.MethodGet, "/cache/check", url.Values{"items[]": hashes}, &payload); err != nil {
+		return cached, err
+	}
+
+	for idx, ok := range payload.Response {
+		if ok && idx < len(hashes) {
+			cached[hashes[idx]] = true
+		}
+	}
+	return cached, nil
+}
+
+func (p *premiumizeService) directLink(ctx context.Context, src string, fileIdx *int) (string, error) {
+	var payload struct {
+		Content []struct {
+			Path string `json:"path"`
+			Size int64  `json:"size"`
+			Link string `json:"link"`
+		} `json:"content"`
+	}
+	if err := p.call(ctx, http.MethodPost, "/transfer/directdl", url.Values{"src": {src}}, &payload); err != nil {
+		return "", err
+	}
+	if len(payload.Content) == 0 {
+		return "", errors.New("premiumize returned no files")
+	}
+
+	files := make([]debridFile, 0, len(payload.Content))
+	for _, item := range payload.Content {
+		files = append(files, debridFile{Path: item.Path, Bytes: item.Size})
+	}
+	link := payload.Content[pickFileIndex(files, fileIdx)].Link
+	if link == "" {
+		return "", errors.New("premiumize returned empty download link")
+	}
+	return link, nil
+}
+
+func (p *premiumizeService) call(ctx context.Context, method string, route string, values url.Values, out any) error {
+	endpoint := premiumizeBase + route
+	var body io.Reader
+	if method == http.MethodGet {
+		if len(values) > 0 {
+			endpoint += "?" + values.Encode()
+		}
+	} else {
+		body = strings.NewReader(values.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.token)
+	req.Header.Set("Accept", "application/json")
+	if method != http.MethodGet {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := p.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return readDebridError("premiumize", resp)
+	}
+
+	var raw json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return err
+	}
+
+	var status struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(raw, &status); err != nil {
+		return err
+	}
+	if status.Status != "success" {
+		return errors.New("premiumize: " + status.Message)
+	}
+	return json.Unmarshal(raw, out)
+}
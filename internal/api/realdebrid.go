@@ -19,24 +19,58 @@ const realDebridBase = "https://api.real-debrid.com/rest/1.0"
 type realDebridService struct {
 	token string
 	http  *http.Client
+	// session replaces token after a device-flow login.
+	session *oauthSession
 }
 
 func newRealDebridService(token string) *realDebridService {
 	return &realDebridService{
 		token: strings.TrimSpace(token),
-		http:  &http.Client{Timeout: 45 * time.Second},
+		http:  debridHTTPClient(),
 	}
 }
 
-func (r *realDebridService) enabled() bool {
-	return strings.TrimSpace(r.token) != ""
+func (r *realDebridService) Name() string {
+	return "Real-Debrid"
 }
 
-func (r *realDebridService) resolveMagnet(ctx context.Context, magnet string, fileIdx *int) (string, error) {
+func (r *realDebridService) Enabled() bool {
+	return strings.TrimSpace(r.token) != "" || r.session != nil
+}
+
+func (r *realDebridService) authorize(ctx context.Context, req *http.Request) error {
+	token := r.token
+	if r.session != nil {
+		var err error
+		if token, err = r.session.accessToken(ctx); err != nil {
+			return err
+		}
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+func (r *realDebridService) ResolveMagnet(ctx context.Context, magnet string, fileIdx *int) (link string, err error) {
+	// Reusing a torrent already in the account only saves work, so a failed
+	// lookup falls back to adding the magnet.
+	existing, _ := r.findTorrents(ctx, infoHashFromMagnet(magnet))
+	for _, torrent := range existing {
+		if link, ok, err := r.reuseTorrent(ctx, torrent, fileIdx); ok {
+			return link, err
+		}
+	}
+
 	torrentID, err := r.addMagnet(ctx, magnet)
 	if err != nil {
 		return "", err
 	}
+	defer func() {
+		// Failed or abandoned torrents would otherwise pile up in the
+		// account; ones still downloading are kept for the next attempt.
+		if errors.Is(err, context.Canceled) || errors.Is(err, errTorrentFailed) {
+			r.discardTorrent(ctx, torrentID)
+		}
+	}()
 
 	info, err := r.waitForTorrentInfo(ctx, torrentID)
 	if err != nil {
@@ -61,7 +95,39 @@ func (r *realDebridService) resolveMagnet(ctx context.Context, magnet string, fi
 		return "", errors.New("torrent has no links")
 	}
 
-	return r.unrestrictLink(ctx, ready.Links[0])
+	return r.UnrestrictLink(ctx, ready.Links[0])
+}
+
+func (r *realDebridService) CheckCache(ctx context.Context, hashes []string) (map[string]bool, error) {
+	cached := map[string]bool{}
+	hashes = uniqueHashes(hashes)
+
+	for start := 0; start < len(hashes); start += 40 {
+		end := min(start+40, len(hashes))
+
+		var payload map[string]json.RawMessage
+		err := r.getJSON(ctx, "/torrents/instantAvailability/"+strings.Join(hashes[start:end], "/"), &payload)
+		if errors.Is(err, errRDDisabled) {
+			// Real-Debrid no longer says what is cached; the addon's [RD+]
+			// marker is all there is to go on.
+			return cached, nil
+		}
+		if err != nil {
+			return cached, err
+		}
+
+		for hash, raw := range payload {
+			var hosts map[string][]json.RawMessage
+			if err := json.Unmarshal(raw, &hosts); err != nil {
+				continue
+			}
+			if len(hosts["rd"]) > 0 {
+				cached[strings.ToLower(hash)] = true
+			}
+		}
+	}
+
+	return cached, nil
 }
 
 func (r *realDebridService) addMagnet(ctx context.Context, magnet string) (string, error) {
@@ -109,6 +175,7 @@ func (r *realDebridService) waitForTorrentInfo(ctx context.Context, torrentID st
 		if len(info.Files) > 0 {
 			return info, nil
 		}
+		reportProgress(ctx, info.progress())
 
 		select {
 		case <-ctx.Done():
@@ -117,10 +184,11 @@ func (r *realDebridService) waitForTorrentInfo(ctx context.Context, torrentID st
 		}
 	}
 
-	return torrentInfo{}, errors.New("torrent metadata did not become available")
+	return torrentInfo{}, fmt.Errorf("%w: torrent metadata did not become available", ErrTimeout)
 }
 
 func (r *realDebridService) waitForReadyLinks(ctx context.Context, torrentID string) (torrentInfo, error) {
+	status := ""
 	for i := 0; i < 30; i++ {
 		info, err := r.torrentInfo(ctx, torrentID)
 		if err != nil {
@@ -131,6 +199,12 @@ func (r *realDebridService) waitForReadyLinks(ctx context.Context, torrentID str
 			return info, nil
 		}
 
+		status = info.Status
+		if torrentFailed(status) {
+			return torrentInfo{}, fmt.Errorf("%w: %s", errTorrentFailed, status)
+		}
+		reportProgress(ctx, info.progress())
+
 		select {
 		case <-ctx.Done():
 			return torrentInfo{}, ctx.Err()
@@ -138,7 +212,22 @@ func (r *realDebridService) waitForReadyLinks(ctx context.Context, torrentID str
 		}
 	}
 
-	return torrentInfo{}, errors.New("timeout waiting for debrid links")
+	if status == "queued" || status == "downloading" {
+		return torrentInfo{}, fmt.Errorf("%w (still %s)", ErrNotCached, status)
+	}
+	return torrentInfo{}, ErrTimeout
+}
+
+// discardTorrent deletes torrentID on a best-effort basis. It runs even when
+// ctx has already been cancelled.
+func (r *realDebridService) discardTorrent(ctx context.Context, torrentID string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+	_ = r.deleteTorrent(ctx, torrentID)
+}
+
+func (r *realDebridService) deleteTorrent(ctx context.Context, torrentID string) error {
+	return r.send(ctx, http.MethodDelete, "/torrents/delete/"+url.PathEscape(torrentID), nil, nil)
 }
 
 func (r *realDebridService) torrentInfo(ctx context.Context, torrentID string) (torrentInfo, error) {
@@ -150,7 +239,7 @@ func (r *realDebridService) torrentInfo(ctx context.Context, torrentID string) (
 	return payload, nil
 }
 
-func (r *realDebridService) unrestrictLink(ctx context.Context, link string) (string, error) {
+func (r *realDebridService) UnrestrictLink(ctx context.Context, link string) (string, error) {
 	var payload struct {
 		Download string `json:"download"`
 	}
@@ -174,7 +263,9 @@ func (r *realDebridService) getJSON(ctx context.Context, route string, out any)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Authorization", "Bearer "+r.token)
+	if err := r.authorize(ctx, req); err != nil {
+		return err
+	}
 	req.Header.Set("Accept", "application/json")
 
 	resp, err := r.http.Do(req)
@@ -184,21 +275,28 @@ func (r *realDebridService) getJSON(ctx context.Context, route string, out any)
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
-		return fmt.Errorf("real-debrid request failed (%d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		return readRealDebridError(resp)
 	}
 
 	return json.NewDecoder(resp.Body).Decode(out)
 }
 
 func (r *realDebridService) postForm(ctx context.Context, route string, values url.Values, out any) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, realDebridBase+route, bytes.NewBufferString(values.Encode()))
+	return r.send(ctx, http.MethodPost, route, values, out)
+}
+
+func (r *realDebridService) send(ctx context.Context, method string, route string, values url.Values, out any) error {
+	req, err := http.NewRequestWithContext(ctx, method, realDebridBase+route, bytes.NewBufferString(values.Encode()))
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+r.token)
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if err := r.authorize(ctx, req); err != nil {
+		return err
+	}
+	if values != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
 	req.Header.Set("Accept", "application/json")
 
 	resp, err := r.http.Do(req)
@@ -208,8 +306,7 @@ func (r *realDebridService) postForm(ctx context.Context, route string, values u
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
-		return fmt.Errorf("real-debrid request failed (%d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		return readRealDebridError(resp)
 	}
 
 	if out == nil {
@@ -220,16 +317,38 @@ func (r *realDebridService) postForm(ctx context.Context, route string, values u
 	return json.NewDecoder(resp.Body).Decode(out)
 }
 
+func readRealDebridError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+
+	var payload struct {
+		Error     string `json:"error"`
+		ErrorCode int    `json:"error_code"`
+	}
+	message := strings.TrimSpace(string(body))
+	if err := json.Unmarshal(body, &payload); err == nil && payload.Error != "" {
+		message = payload.Error
+	}
+	return realDebridError(resp.StatusCode, payload.ErrorCode, message)
+}
+
 type torrentInfo struct {
-	Status string `json:"status"`
-	Files  []struct {
-		ID    int    `json:"id"`
-		Path  string `json:"path"`
-		Bytes int64  `json:"bytes"`
+	Status   string  `json:"status"`
+	Progress float64 `json:"progress"`
+	Speed    int64   `json:"speed"`
+	Seeders  int     `json:"seeders"`
+	Files    []struct {
+		ID       int    `json:"id"`
+		Path     string `json:"path"`
+		Bytes    int64  `json:"bytes"`
+		Selected int    `json:"selected"`
 	} `json:"files"`
 	Links []string `json:"links"`
 }
 
+func (t torrentInfo) progress() ResolveProgress {
+	return ResolveProgress{Status: t.Status, Percent: t.Progress, Speed: t.Speed, Seeders: t.Seeders}
+}
+
 func buildMagnet(stream Stream) string {
 	if stream.InfoHash == "" {
 		return ""
@@ -256,34 +375,16 @@ func buildMagnet(stream Stream) string {
 }
 
 func pickFileID(info torrentInfo, fileIdx *int) int {
-	if len(info.Files) == 0 {
-		return 0
-	}
-
-	if fileIdx != nil {
-		idx := *fileIdx
-		if idx >= 0 && idx < len(info.Files) {
-			return info.Files[idx].ID
-		}
-	}
-
-	bestID := 0
-	bestBytes := int64(-1)
+	files := make([]debridFile, 0, len(info.Files))
 	for _, file := range info.Files {
-		if !isLikelyVideo(file.Path) {
-			continue
-		}
-		if file.Bytes > bestBytes {
-			bestID = file.ID
-			bestBytes = file.Bytes
-		}
+		files = append(files, debridFile{Path: file.Path, Bytes: file.Bytes})
 	}
 
-	if bestID != 0 {
-		return bestID
+	idx := pickFileIndex(files, fileIdx)
+	if idx < 0 {
+		return 0
 	}
-
-	return info.Files[0].ID
+	return info.Files[idx].ID
 }
 
 func isLikelyVideo(filePath string) bool {
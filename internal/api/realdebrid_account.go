@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// Account is the Real-Debrid user profile from /user.
+type Account struct {
+	Username   string    `json:"username"`
+	Email      string    `json:"email"`
+	Points     int       `json:"points"`
+	Type       string    `json:"type"`
+	Premium    int64     `json:"premium"`
+	Expiration time.Time `json:"expiration"`
+}
+
+// PremiumLeft is how long the premium subscription still runs.
+func (a Account) PremiumLeft() time.Duration {
+	return time.Duration(a.Premium) * time.Second
+}
+
+// HostTraffic is the usage and limit for one hoster from /traffic.
+type HostTraffic struct {
+	Host  string
+	Left  int64  `json:"left"`
+	Bytes int64  `json:"bytes"`
+	Links int    `json:"links"`
+	Limit int64  `json:"limit"`
+	Type  string `json:"type"`
+	Reset string `json:"reset"`
+}
+
+// Download is an entry in the Real-Debrid downloads list: a link that has
+// already been unrestricted.
+type Download struct {
+	ID        string    `json:"id"`
+	Filename  string    `json:"filename"`
+	Filesize  int64     `json:"filesize"`
+	Link      string    `json:"link"`
+	Host      string    `json:"host"`
+	Download  string    `json:"download"`
+	Generated time.Time `json:"generated"`
+}
+
+// RealDebridAccount returns the profile and per-host traffic of the logged
+// in Real-Debrid user.
+func (c *Client) RealDebridAccount(ctx context.Context) (Account, []HostTraffic, error) {
+	if !c.RealDebridEnabled() {
+		return Account{}, nil, errors.New("real-debrid is not configured")
+	}
+
+	var account Account
+	if err := c.rd.getJSON(ctx, "/user", &account); err != nil {
+		return Account{}, nil, err
+	}
+
+	traffic, err := c.rd.traffic(ctx)
+	return account, traffic, err
+}
+
+// RealDebridDownloads lists the most recent entries in the downloads list.
+func (c *Client) RealDebridDownloads(ctx context.Context, limit int) ([]Download, error) {
+	if !c.RealDebridEnabled() {
+		return nil, errors.New("real-debrid is not configured")
+	}
+
+	var downloads []Download
+	query := url.Values{"limit": {fmt.Sprint(limit)}}
+	if err := c.rd.getJSON(ctx, "/downloads?"+query.Encode(), &downloads); err != nil {
+		return nil, err
+	}
+	return downloads, nil
+}
+
+func (r *realDebridService) traffic(ctx context.Context) ([]HostTraffic, error) {
+	var payload map[string]json.RawMessage
+	if err := r.getJSON(ctx, "/traffic", &payload); err != nil {
+		return nil, err
+	}
+
+	hosts := make([]HostTraffic, 0, len(payload))
+	for host, raw := range payload {
+		var traffic HostTraffic
+		if err := json.Unmarshal(raw, &traffic); err != nil {
+			// Top-level counters such as remote_traffic are plain numbers.
+			continue
+		}
+		traffic.Host = host
+		hosts = append(hosts, traffic)
+	}
+
+	sort.Slice(hosts, func(i, j int) bool {
+		if (hosts[i].Bytes > 0) != (hosts[j].Bytes > 0) {
+			return hosts[i].Bytes > 0
+		}
+		return hosts[i].Host < hosts[j].Host
+	})
+	return hosts, nil
+}
@@ -0,0 +1,216 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	realDebridOAuthBase = "https://api.real-debrid.com/oauth/v2"
+	// realDebridClientID is Real-Debrid's public client id for open-source
+	// apps. The device flow hands out a per-user client id and secret.
+	realDebridClientID = "X245A4XAIBGVM"
+	deviceGrantType    = "http://oauth.net/grant_type/device/1.0"
+)
+
+// DeviceCode is what the user needs to approve tuiflix on the Real-Debrid
+// website.
+type DeviceCode struct {
+	DeviceCode      string
+	UserCode        string
+	VerificationURL string
+	Interval        time.Duration
+	ExpiresAt       time.Time
+}
+
+// OAuthToken holds the credentials obtained through the device flow.
+type OAuthToken struct {
+	ClientID     string    `json:"client_id"`
+	ClientSecret string    `json:"client_secret"`
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// Expired reports whether the access token is due for a refresh.
+func (t OAuthToken) Expired(now time.Time) bool {
+	return t.AccessToken == "" || now.Add(time.Minute).After(t.ExpiresAt)
+}
+
+// RealDebridOAuth runs Real-Debrid's device-code OAuth flow.
+type RealDebridOAuth struct {
+	base string
+	http *http.Client
+}
+
+// NewRealDebridOAuth returns a client for the OAuth endpoints under base, or
+// Real-Debrid's own when base is empty.
+func NewRealDebridOAuth(base string) *RealDebridOAuth {
+	if base == "" {
+		base = realDebridOAuthBase
+	}
+	return &RealDebridOAuth{
+		base: strings.TrimRight(base, "/"),
+		http: debridHTTPClient(),
+	}
+}
+
+func (o *RealDebridOAuth) StartDevice(ctx context.Context) (DeviceCode, error) {
+	var payload struct {
+		DeviceCode      string `json:"device_code"`
+		UserCode        string `json:"user_code"`
+		Interval        int    `json:"interval"`
+		ExpiresIn       int    `json:"expires_in"`
+		VerificationURL string `json:"verification_url"`
+	}
+
+	query := url.Values{"client_id": {realDebridClientID}, "new_credentials": {"yes"}}
+	if _, err := o.do(ctx, http.MethodGet, "/device/code?"+query.Encode(), nil, &payload); err != nil {
+		return DeviceCode{}, err
+	}
+	if payload.DeviceCode == "" || payload.UserCode == "" {
+		return DeviceCode{}, errors.New("real-debrid returned an empty device code")
+	}
+
+	interval := time.Duration(payload.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	return DeviceCode{
+		DeviceCode:      payload.DeviceCode,
+		UserCode:        payload.UserCode,
+		VerificationURL: payload.VerificationURL,
+		Interval:        interval,
+		ExpiresAt:       time.Now().Add(time.Duration(payload.ExpiresIn) * time.Second),
+	}, nil
+}
+
+// WaitForToken polls until the user approves code, then exchanges it for an
+// access token.
+func (o *RealDebridOAuth) WaitForToken(ctx context.Context, code DeviceCode) (OAuthToken, error) {
+	var creds struct {
+		ClientID     string `json:"client_id"`
+		ClientSecret string `json:"client_secret"`
+	}
+
+	query := url.Values{"client_id": {realDebridClientID}, "code": {code.DeviceCode}}
+	for creds.ClientID == "" {
+		if !code.ExpiresAt.IsZero() && time.Now().After(code.ExpiresAt) {
+			return OAuthToken{}, fmt.Errorf("%w: device code expired before it was approved", ErrTimeout)
+		}
+
+		status, err := o.do(ctx, http.MethodGet, "/device/credentials?"+query.Encode(), nil, &creds)
+		if err != nil && status != http.StatusForbidden {
+			return OAuthToken{}, err
+		}
+		if creds.ClientID != "" {
+			break
+		}
+		if err := waitPoll(ctx, code.Interval); err != nil {
+			return OAuthToken{}, err
+		}
+	}
+
+	return o.exchange(ctx, creds.ClientID, creds.ClientSecret, code.DeviceCode)
+}
+
+// Refresh trades the refresh token for a new access token.
+func (o *RealDebridOAuth) Refresh(ctx context.Context, token OAuthToken) (OAuthToken, error) {
+	if token.RefreshToken == "" {
+		return OAuthToken{}, fmt.Errorf("real-debrid: %w (no refresh token, log in again)", ErrDebridAuth)
+	}
+	return o.exchange(ctx, token.ClientID, token.ClientSecret, token.RefreshToken)
+}
+
+func (o *RealDebridOAuth) exchange(ctx context.Context, clientID string, clientSecret string, code string) (OAuthToken, error) {
+	var payload struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+
+	values := url.Values{
+		"client_id":     {clientID},
+		"client_secret": {clientSecret},
+		"code":          {code},
+		"grant_type":    {deviceGrantType},
+	}
+	if _, err := o.do(ctx, http.MethodPost, "/token", values, &payload); err != nil {
+		return OAuthToken{}, err
+	}
+	if payload.AccessToken == "" {
+		return OAuthToken{}, errors.New("real-debrid returned an empty access token")
+	}
+
+	return OAuthToken{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		AccessToken:  payload.AccessToken,
+		RefreshToken: payload.RefreshToken,
+		ExpiresAt:    time.Now().Add(time.Duration(payload.ExpiresIn) * time.Second),
+	}, nil
+}
+
+func (o *RealDebridOAuth) do(ctx context.Context, method string, route string, values url.Values, out any) (int, error) {
+	var body *bytes.Buffer
+	if values != nil {
+		body = bytes.NewBufferString(values.Encode())
+	} else {
+		body = &bytes.Buffer{}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, o.base+route, body)
+	if err != nil {
+		return 0, err
+	}
+	if values != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := o.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return resp.StatusCode, readRealDebridError(resp)
+	}
+	return resp.StatusCode, json.NewDecoder(resp.Body).Decode(out)
+}
+
+// oauthSession keeps the access token of a device-flow login fresh.
+type oauthSession struct {
+	mu        sync.Mutex
+	oauth     *RealDebridOAuth
+	token     OAuthToken
+	onRefresh func(OAuthToken)
+}
+
+func (s *oauthSession) accessToken(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.token.Expired(time.Now()) {
+		return s.token.AccessToken, nil
+	}
+
+	token, err := s.oauth.Refresh(ctx, s.token)
+	if err != nil {
+		return "", fmt.Errorf("refresh real-debrid login: %w", err)
+	}
+	s.token = token
+	if s.onRefresh != nil {
+		s.onRefresh(token)
+	}
+	return token.AccessToken, nil
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRealDebridDeviceLogin(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/device/code":
+			if r.URL.Query().Get("client_id") != realDebridClientID {
+				t.Errorf("unexpected client id %q", r.URL.Query().Get("client_id"))
+			}
+			fmt.Fprint(w, `{"device_code":"DEV","user_code":"ABCD1234","interval":5,"expires_in":600,"verification_url":"https://real-debrid.com/device"}`)
+		case "/device/credentials":
+			polls++
+			if polls < 3 {
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, `{"error":"authorization_pending","error_code":8}`)
+				return
+			}
+			fmt.Fprint(w, `{"client_id":"USER-ID","client_secret":"SECRET"}`)
+		case "/token":
+			if err := r.ParseForm(); err != nil {
+				t.Error(err)
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if r.PostForm.Get("client_id") != "USER-ID" || r.PostForm.Get("grant_type") != deviceGrantType {
+				t.Errorf("unexpected token request: %v", r.PostForm)
+			}
+			switch r.PostForm.Get("code") {
+			case "DEV":
+				fmt.Fprint(w, `{"access_token":"ACCESS-1","refresh_token":"REFRESH","expires_in":3600,"token_type":"Bearer"}`)
+			case "REFRESH":
+				fmt.Fprint(w, `{"access_token":"ACCESS-2","refresh_token":"REFRESH","expires_in":3600,"token_type":"Bearer"}`)
+			default:
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	oauth := NewRealDebridOAuth(server.URL)
+	code, err := oauth.StartDevice(ctx)
+	if err != nil {
+		t.Fatalf("StartDevice: %v", err)
+	}
+	if code.UserCode != "ABCD1234" || code.VerificationURL != "https://real-debrid.com/device" {
+		t.Fatalf("unexpected device code: %+v", code)
+	}
+
+	code.Interval = time.Millisecond
+	token, err := oauth.WaitForToken(ctx, code)
+	if err != nil {
+		t.Fatalf("WaitForToken: %v", err)
+	}
+	if token.AccessToken != "ACCESS-1" || token.ClientSecret != "SECRET" || polls != 3 {
+		t.Fatalf("unexpected token %+v after %d polls", token, polls)
+	}
+
+	var refreshed OAuthToken
+	token.ExpiresAt = time.Now()
+	session := &oauthSession{oauth: oauth, token: token, onRefresh: func(t OAuthToken) { refreshed = t }}
+	access, err := session.accessToken(ctx)
+	if err != nil {
+		t.Fatalf("accessToken: %v", err)
+	}
+	if access != "ACCESS-2" || refreshed.AccessToken != "ACCESS-2" {
+		t.Fatalf("expected refreshed token, got %q (stored %q)", access, refreshed.AccessToken)
+	}
+
+	client, err := NewClient(Config{
+		Addons:          []string{"https://torrentio.strem.fun"},
+		Torrentio:       TorrentioOptions{DebridKey: true},
+		RealDebridLogin: &token,
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	client.rd.session.oauth = oauth
+	if err := client.refreshTorrentioToken(ctx); err != nil {
+		t.Fatalf("refreshTorrentioToken: %v", err)
+	}
+	if path := client.addons[0].configPath; path != "realdebrid=ACCESS-2" {
+		t.Fatalf("expected the refreshed token in the Torrentio path, got %q", path)
+	}
+}
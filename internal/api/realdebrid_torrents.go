@@ -0,0 +1,195 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// staleAfter is how long a torrent may sit waiting for file selection or
+// magnet conversion before prune treats it as abandoned.
+const staleAfter = time.Hour
+
+var errTorrentFailed = errors.New("real-debrid torrent failed")
+
+// Torrent is an entry in the Real-Debrid torrents list.
+type Torrent struct {
+	ID       string    `json:"id"`
+	Filename string    `json:"filename"`
+	Hash     string    `json:"hash"`
+	Bytes    int64     `json:"bytes"`
+	Status   string    `json:"status"`
+	Progress float64   `json:"progress"`
+	Added    time.Time `json:"added"`
+	Links    []string  `json:"links"`
+}
+
+// Failed reports whether Real-Debrid gave up on the torrent.
+func (t Torrent) Failed() bool {
+	return torrentFailed(t.Status)
+}
+
+func torrentFailed(status string) bool {
+	switch status {
+	case "magnet_error", "error", "virus", "dead":
+		return true
+	}
+	return false
+}
+
+// StaleTorrents picks the torrents prune should remove: failed ones and ones
+// stuck before downloading for longer than staleAfter. Torrents sharing a
+// hash are kept, since resolving another file of a pack adds the magnet
+// again with a different selection.
+func StaleTorrents(torrents []Torrent, now time.Time) []Torrent {
+	var stale []Torrent
+	for _, torrent := range torrents {
+		switch {
+		case torrent.Failed():
+			stale = append(stale, torrent)
+		case (torrent.Status == "waiting_files_selection" || torrent.Status == "magnet_conversion") && now.Sub(torrent.Added) > staleAfter:
+			stale = append(stale, torrent)
+		}
+	}
+	return stale
+}
+
+// RealDebridTorrents lists every torrent in the Real-Debrid account, newest
+// first.
+func (c *Client) RealDebridTorrents(ctx context.Context) ([]Torrent, error) {
+	if !c.RealDebridEnabled() {
+		return nil, errors.New("real-debrid is not configured")
+	}
+	return c.rd.listTorrents(ctx)
+}
+
+func (c *Client) DeleteRealDebridTorrent(ctx context.Context, id string) error {
+	if !c.RealDebridEnabled() {
+		return errors.New("real-debrid is not configured")
+	}
+	return c.rd.deleteTorrent(ctx, id)
+}
+
+// TorrentFile is a selected file of a torrent together with the hoster link
+// Real-Debrid generated for it. Link is empty until the torrent finished.
+type TorrentFile struct {
+	Path  string
+	Bytes int64
+	Link  string
+}
+
+// RealDebridTorrentFiles lists the files of torrent id that were selected
+// for download.
+func (c *Client) RealDebridTorrentFiles(ctx context.Context, id string) ([]TorrentFile, error) {
+	if !c.RealDebridEnabled() {
+		return nil, errors.New("real-debrid is not configured")
+	}
+
+	info, err := c.rd.torrentInfo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []TorrentFile
+	for _, file := range info.Files {
+		if file.Selected != 1 {
+			continue
+		}
+		entry := TorrentFile{Path: strings.TrimPrefix(file.Path, "/"), Bytes: file.Bytes}
+		if len(files) < len(info.Links) {
+			entry.Link = info.Links[len(files)]
+		}
+		files = append(files, entry)
+	}
+	return files, nil
+}
+
+func (r *realDebridService) listTorrents(ctx context.Context) ([]Torrent, error) {
+	const pageSize = 500
+
+	var all []Torrent
+	for page := 1; ; page++ {
+		var batch []Torrent
+		query := url.Values{"page": {fmt.Sprint(page)}, "limit": {fmt.Sprint(pageSize)}}
+		if err := r.getJSON(ctx, "/torrents?"+query.Encode(), &batch); err != nil {
+			return all, err
+		}
+		all = append(all, batch...)
+		if len(batch) < pageSize {
+			return all, nil
+		}
+	}
+}
+
+// findTorrents lists the torrents with hash in the account that have not
+// failed, newest first. Ones still waiting for a file selection come last,
+// so a torrent that already has the file is reused before another file
+// gets selected.
+func (r *realDebridService) findTorrents(ctx context.Context, hash string) ([]Torrent, error) {
+	if hash == "" {
+		return nil, nil
+	}
+	torrents, err := r.listTorrents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var ready, waiting []Torrent
+	for _, torrent := range torrents {
+		switch {
+		case !strings.EqualFold(torrent.Hash, hash) || torrent.Failed():
+		case torrent.Status == "waiting_files_selection":
+			waiting = append(waiting, torrent)
+		default:
+			ready = append(ready, torrent)
+		}
+	}
+	return append(ready, waiting...), nil
+}
+
+// reuseTorrent resolves fileIdx from a torrent already in the account,
+// selecting the file first when the torrent still waits for a selection. ok
+// is false when the torrent downloads other files, in which case the caller
+// should add the magnet again.
+func (r *realDebridService) reuseTorrent(ctx context.Context, torrent Torrent, fileIdx *int) (link string, ok bool, err error) {
+	info, err := r.torrentInfo(ctx, torrent.ID)
+	if err != nil || len(info.Files) == 0 {
+		return "", false, nil
+	}
+
+	fileID := pickFileID(info, fileIdx)
+	linkIdx, selected := -1, 0
+	for _, file := range info.Files {
+		if file.Selected != 1 {
+			continue
+		}
+		if file.ID == fileID {
+			linkIdx = selected
+		}
+		selected++
+	}
+	if linkIdx < 0 {
+		if info.Status != "waiting_files_selection" || fileID == 0 {
+			return "", false, nil
+		}
+		if err := r.selectFiles(ctx, torrent.ID, []int{fileID}); err != nil {
+			return "", true, err
+		}
+		linkIdx, info.Links = 0, nil
+	}
+
+	if len(info.Links) == 0 {
+		info, err = r.waitForReadyLinks(ctx, torrent.ID)
+		if err != nil {
+			return "", true, err
+		}
+	}
+	if linkIdx >= len(info.Links) {
+		return "", false, nil
+	}
+
+	link, err = r.UnrestrictLink(ctx, info.Links[linkIdx])
+	return link, true, err
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStaleTorrents(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	torrents := []Torrent{
+		{ID: "new", Hash: "AAA", Status: "downloaded", Added: now.Add(-time.Hour)},
+		{ID: "dupe", Hash: "aaa", Status: "downloaded", Added: now.Add(-48 * time.Hour)},
+		{ID: "dead", Hash: "bbb", Status: "dead", Added: now.Add(-time.Minute)},
+		{ID: "stuck", Hash: "ccc", Status: "waiting_files_selection", Added: now.Add(-3 * time.Hour)},
+		{ID: "fresh", Hash: "ddd", Status: "magnet_conversion", Added: now.Add(-10 * time.Minute)},
+		{ID: "busy", Hash: "eee", Status: "downloading", Added: now.Add(-72 * time.Hour)},
+	}
+
+	got := map[string]bool{}
+	for _, torrent := range StaleTorrents(torrents, now) {
+		got[torrent.ID] = true
+	}
+
+	for _, id := range []string{"dead", "stuck"} {
+		if !got[id] {
+			t.Errorf("expected %s to be stale", id)
+		}
+	}
+	for _, id := range []string{"new", "dupe", "fresh", "busy"} {
+		if got[id] {
+			t.Errorf("expected %s to be kept", id)
+		}
+	}
+}
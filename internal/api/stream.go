@@ -0,0 +1,170 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func streamID(item MediaItem, season int, episode int) (string, error) {
+	if item.ID == "" {
+		return "", errors.New("missing media id")
+	}
+
+	switch item.Type {
+	case "movie":
+		return item.ID, nil
+	case "series":
+		return fmt.Sprintf("%s:%d:%d", item.ID, season, episode), nil
+	default:
+		return "", fmt.Errorf("unsupported media type: %s", item.Type)
+	}
+}
+
+func (a *addonClient) fetchStreams(ctx context.Context, mediaType string, id string) ([]Stream, error) {
+	var payload struct {
+		Streams []struct {
+			Name     string          `json:"name"`
+			Title    string          `json:"title"`
+			URL      string          `json:"url"`
+			InfoHash string          `json:"infoHash"`
+			FileIdx  json.RawMessage `json:"fileIdx"`
+			Sources  []string        `json:"sources"`
+		} `json:"streams"`
+	}
+
+	if err := a.fetchResource(ctx, "stream", mediaType, id, nil, &payload); err != nil {
+		return nil, err
+	}
+
+	source := a.name()
+	streams := make([]Stream, 0, len(payload.Streams))
+	for _, raw := range payload.Streams {
+		idx := parseOptionalInt(raw.FileIdx)
+		entry := Stream{
+			Name:     strings.TrimSpace(raw.Name),
+			Title:    strings.TrimSpace(raw.Title),
+			URL:      strings.TrimSpace(raw.URL),
+			InfoHash: strings.TrimSpace(raw.InfoHash),
+			FileIdx:  idx,
+			Sources:  raw.Sources,
+			Addon:    source,
+		}
+		entry.Info = ParseStreamInfo(entry.Name, entry.Title)
+		entry.Cached = hasCachedMarker(entry.Name)
+
+		if entry.URL == "" && entry.InfoHash == "" {
+			continue
+		}
+
+		streams = append(streams, entry)
+	}
+
+	return streams, nil
+}
+
+// hasCachedMarker detects the "[RD+]"-style tag debrid-aware addons put in
+// the stream name when the torrent is already in the provider's cache.
+func hasCachedMarker(name string) bool {
+	if !strings.HasPrefix(name, "[") {
+		return false
+	}
+	end := strings.Index(name, "]")
+	return end > 0 && strings.HasSuffix(name[:end], "+")
+}
+
+// mergeStreams concatenates per-addon results in addon order, dropping
+// torrents already seen with the same info hash and file index.
+func mergeStreams(results [][]Stream) []Stream {
+	seen := map[string]struct{}{}
+	merged := make([]Stream, 0)
+	for _, streams := range results {
+		for _, stream := range streams {
+			if stream.InfoHash != "" {
+				key := strings.ToLower(stream.InfoHash) + "/-"
+				if stream.FileIdx != nil {
+					key = fmt.Sprintf("%s/%d", strings.ToLower(stream.InfoHash), *stream.FileIdx)
+				}
+				if _, ok := seen[key]; ok {
+					continue
+				}
+				seen[key] = struct{}{}
+			}
+			merged = append(merged, stream)
+		}
+	}
+	return merged
+}
+
+type SourceError struct {
+	Source string
+	Err    error
+}
+
+func (e SourceError) Error() string {
+	return e.Source + ": " + e.Err.Error()
+}
+
+func (e SourceError) Unwrap() error {
+	return e.Err
+}
+
+// SourceErrors collects the addons that failed during an aggregated request.
+// It may be returned together with results from the addons that succeeded.
+type SourceErrors []SourceError
+
+func (e SourceErrors) Error() string {
+	parts := make([]string, 0, len(e))
+	for _, err := range e {
+		parts = append(parts, err.Error())
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Summary is a short per-source description suitable for a status line.
+func (e SourceErrors) Summary() string {
+	parts := make([]string, 0, len(e))
+	for _, err := range e {
+		reason := "failed"
+		if errors.Is(err.Err, context.DeadlineExceeded) {
+			reason = "timed out"
+		}
+		parts = append(parts, err.Source+" "+reason)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func parseOptionalInt(raw json.RawMessage) *int {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+
+	if raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil
+		}
+		return &n
+	}
+
+	var n int
+	if err := json.Unmarshal(raw, &n); err == nil {
+		return &n
+	}
+
+	var f float64
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return nil
+	}
+	n = int(f)
+	return &n
+}
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// StreamInfo is the metadata Torrentio-style addons pack into a stream's
+// name and title lines.
+type StreamInfo struct {
+	Filename    string
+	Resolution  string
+	Source      string
+	Codec       string
+	HDR         bool
+	DolbyVision bool
+	Audio       string
+	Size        int64
+	Seeders     int
+	Provider    string
+	Languages   []string
+}
+
+var (
+	resolutionPattern = regexp.MustCompile(`(?i)\b(2160|1440|1080|720|576|480|360)p\b`)
+	channelsPattern   = regexp.MustCompile(`(?:^|[^0-9])([2-7]\.[01])(?:[^0-9]|$)`)
+	seedersPattern    = regexp.MustCompile(`👤\s*(\d+)`)
+	sizePattern       = regexp.MustCompile(`💾\s*([\d.,]+)\s*([KMGT]i?B)`)
+	providerPattern   = regexp.MustCompile(`⚙\x{FE0F}?\s*(.+)$`)
+	flagPattern       = regexp.MustCompile(`[\x{1F1E6}-\x{1F1FF}]{2}`)
+	tokenSplitPattern = regexp.MustCompile(`[\s._\-\[\]()+|/]+`)
+)
+
+var sourceTokens = []struct {
+	source string
+	tokens []string
+}{
+	{"REMUX", []string{"remux"}},
+	{"BluRay", []string{"bluray", "blu-ray", "bdrip", "brrip", "bdremux"}},
+	{"WEB-DL", []string{"web-dl", "webdl"}},
+	{"WEBRip", []string{"webrip", "web-rip", "web"}},
+	{"HDTV", []string{"hdtv"}},
+	{"DVDRip", []string{"dvdrip", "dvd"}},
+	{"CAM", []string{"cam", "camrip", "hdcam"}},
+	{"TS", []string{"ts", "telesync", "hdts", "tc", "telecine"}},
+	{"SCR", []string{"scr", "screener", "dvdscr"}},
+}
+
+var codecTokens = []struct {
+	codec  string
+	tokens []string
+}{
+	{"HEVC", []string{"x265", "h265", "hevc"}},
+	{"AVC", []string{"x264", "h264", "avc"}},
+	{"AV1", []string{"av1"}},
+	{"XviD", []string{"xvid"}},
+}
+
+func ParseStreamInfo(name string, title string) StreamInfo {
+	info := StreamInfo{}
+
+	lines := strings.Split(strings.TrimSpace(title), "\n")
+	for idx, line := range lines {
+		line = strings.TrimSpace(line)
+		switch {
+		case idx == 0:
+			info.Filename = line
+		case strings.Contains(line, "👤") || strings.Contains(line, "💾") || strings.Contains(line, "⚙"):
+			parseStatsLine(&info, line)
+		case flagPattern.MatchString(line) || strings.Contains(strings.ToLower(line), "multi audio"):
+			parseLanguageLine(&info, line)
+		case looksLikeFilename(line):
+			// Season packs list the pack first and the episode file second.
+			info.Filename = line
+		}
+	}
+
+	release := strings.Join(lines[:1], " ")
+	if info.Filename != "" && info.Filename != release {
+		release += " " + info.Filename
+	}
+	haystack := name + " " + release
+
+	if match := resolutionPattern.FindStringSubmatch(haystack); match != nil {
+		info.Resolution = match[1] + "p"
+	}
+
+	tokens := map[string]bool{}
+	for _, token := range tokenSplitPattern.Split(strings.ToLower(haystack), -1) {
+		if token != "" {
+			tokens[token] = true
+		}
+	}
+	lower := strings.ToLower(haystack)
+
+	if info.Resolution == "" && (tokens["4k"] || tokens["uhd"]) {
+		info.Resolution = "2160p"
+	}
+
+	for _, candidate := range sourceTokens {
+		if info.Source != "" {
+			break
+		}
+		for _, token := range candidate.tokens {
+			if tokens[token] || (strings.Contains(token, "-") && strings.Contains(lower, token)) {
+				info.Source = candidate.source
+				break
+			}
+		}
+	}
+	if info.Source == "WEBRip" && tokens["web"] && tokens["dl"] {
+		info.Source = "WEB-DL"
+	}
+
+	for _, candidate := range codecTokens {
+		for _, token := range candidate.tokens {
+			if tokens[token] {
+				info.Codec = candidate.codec
+				break
+			}
+		}
+		if info.Codec != "" {
+			break
+		}
+	}
+
+	info.DolbyVision = tokens["dv"] || tokens["dovi"] || strings.Contains(lower, "dolby vision") || strings.Contains(lower, "dolby.vision")
+	info.HDR = tokens["hdr"] || tokens["hdr10"] || tokens["hdr10plus"] || strings.Contains(lower, "hdr10+")
+
+	if match := channelsPattern.FindStringSubmatch(release); match != nil {
+		info.Audio = match[1]
+	}
+	if tokens["atmos"] {
+		info.Audio = strings.TrimSpace(info.Audio + " Atmos")
+	}
+
+	return info
+}
+
+func parseStatsLine(info *StreamInfo, line string) {
+	if match := seedersPattern.FindStringSubmatch(line); match != nil {
+		info.Seeders, _ = strconv.Atoi(match[1])
+	}
+	if match := sizePattern.FindStringSubmatch(line); match != nil {
+		info.Size = parseSize(match[1], match[2])
+	}
+
+	idx := strings.Index(line, "⚙")
+	if idx < 0 {
+		return
+	}
+	if match := providerPattern.FindStringSubmatch(line[idx:]); match != nil {
+		info.Provider = strings.TrimSpace(match[1])
+	}
+}
+
+func parseLanguageLine(info *StreamInfo, line string) {
+	if strings.Contains(strings.ToLower(line), "multi audio") {
+		info.Languages = append(info.Languages, "Multi")
+	}
+	info.Languages = append(info.Languages, flagPattern.FindAllString(line, -1)...)
+}
+
+func parseSize(value string, unit string) int64 {
+	number, err := strconv.ParseFloat(strings.ReplaceAll(value, ",", ""), 64)
+	if err != nil {
+		return 0
+	}
+
+	multiplier := float64(1)
+	switch strings.ToUpper(unit[:1]) {
+	case "K":
+		multiplier = 1 << 10
+	case "M":
+		multiplier = 1 << 20
+	case "G":
+		multiplier = 1 << 30
+	case "T":
+		multiplier = 1 << 40
+	}
+	return int64(number * multiplier)
+}
+
+func looksLikeFilename(line string) bool {
+	return isLikelyVideo(line) || resolutionPattern.MatchString(line)
+}
+
+// ResolutionRank orders resolutions from best to worst; unknown is lowest.
+func (i StreamInfo) ResolutionRank() int {
+	if i.Resolution == "" {
+		return 0
+	}
+	rank, _ := strconv.Atoi(strings.TrimSuffix(i.Resolution, "p"))
+	return rank
+}
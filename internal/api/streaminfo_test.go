@@ -0,0 +1,66 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseStreamInfo(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		want  StreamInfo
+	}{
+		{
+			name:  "Torrentio\n4k DV | HDR",
+			title: "The.Matrix.1999.2160p.UHD.BluRay.REMUX.DV.HDR.HEVC.TrueHD.7.1.Atmos-FGT\n👤 35 💾 63.17 GB ⚙️ ThePirateBay\nMulti Audio / 🇬🇧 / 🇫🇷",
+			want: StreamInfo{
+				Filename:    "The.Matrix.1999.2160p.UHD.BluRay.REMUX.DV.HDR.HEVC.TrueHD.7.1.Atmos-FGT",
+				Resolution:  "2160p",
+				Source:      "REMUX",
+				Codec:       "HEVC",
+				HDR:         true,
+				DolbyVision: true,
+				Audio:       "7.1 Atmos",
+				Size:        67828271022,
+				Seeders:     35,
+				Provider:    "ThePirateBay",
+				Languages:   []string{"Multi", "🇬🇧", "🇫🇷"},
+			},
+		},
+		{
+			name:  "[RD+] Torrentio\n1080p",
+			title: "Game.of.Thrones.S03.1080p.WEB-DL.DDP5.1.x264\nGame.of.Thrones.S03E09.The.Rains.of.Castamere.1080p.WEB-DL.DDP5.1.x264.mkv\n👤 12 💾 2.1 GB ⚙️ 1337x\n🇬🇧 / 🇩🇪",
+			want: StreamInfo{
+				Filename:   "Game.of.Thrones.S03E09.The.Rains.of.Castamere.1080p.WEB-DL.DDP5.1.x264.mkv",
+				Resolution: "1080p",
+				Source:     "WEB-DL",
+				Codec:      "AVC",
+				Audio:      "5.1",
+				Size:       2254857830,
+				Seeders:    12,
+				Provider:   "1337x",
+				Languages:  []string{"🇬🇧", "🇩🇪"},
+			},
+		},
+		{
+			name:  "Torrentio\nCAM",
+			title: "Some.Movie.2024.HDCAM.x264\n👤 3 💾 850 MB ⚙️ YTS",
+			want: StreamInfo{
+				Filename: "Some.Movie.2024.HDCAM.x264",
+				Source:   "CAM",
+				Codec:    "AVC",
+				Size:     891289600,
+				Seeders:  3,
+				Provider: "YTS",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		got := ParseStreamInfo(tt.name, tt.title)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseStreamInfo(%q)\n got  %+v\n want %+v", tt.title, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const torBoxBase = "https://api.torbox.app/v1/api"
+
+type torBoxService struct {
+	token string
+	http  *http.Client
+}
+
+func newTorBoxService(token string) *torBoxService {
+	return &torBoxService{token: token, http: debridHTTPClient()}
+}
+
+func (t *torBoxService) Name() string {
+	return "TorBox"
+}
+
+func (t *torBoxService) Enabled() bool {
+	return t.token != ""
+}
+
+func (t *torBoxService) ResolveMagnet(ctx context.Context, magnet string, fileIdx *int) (string, error) {
+	var created struct {
+		TorrentID int64 `json:"torrent_id"`
+	}
+	if err := t.postMultipart(ctx, "/torrents/createtorrent", map[string]string{"magnet": magnet}, &created); err != nil {
+		return "", err
+	}
+
+	id := fmt.Sprint(created.TorrentID)
+	for i := 0; i < 40; i++ {
+		var info struct {
+			DownloadFinished bool   `json:"download_finished"`
+			DownloadState    string `json:"download_state"`
+			Files            []struct {
+				ID   int64  `json:"id"`
+				Name string `json:"name"`
+				Size int64  `json:"size"`
+			} `json:"files"`
+		}
+		if err := t.get(ctx, "/torrents/mylist", url.Values{"id": {id}, "bypass_cache": {"true"}}, &info); err != nil {
+			return "", err
+		}
+
+		if info.DownloadFinished && len(info.Files) > 0 {
+			files := make([]debridFile, 0, len(info.Files))
+			for _, file := range info.Files {
+				files = append(files, debridFile{Path: file.Name, Bytes: file.Size})
+			}
+			fileID := info.Files[pickFileIndex(files, fileIdx)].ID
+
+			var link string
+			values := url.Values{"token": {t.token}, "torrent_id": {id}, "file_id": {fmt.Sprint(fileID)}}
+			if err := t.get(ctx, "/torrents/requestdl", values, &link); err != nil {
+				return "", err
+			}
+			return link, nil
+		}
+		if strings.Contains(info.DownloadState, "error") || info.DownloadState == "failed" {
+			return "", errors.New("torbox: " + info.DownloadState)
+		}
+
+		if err := waitPoll(ctx, 1500*time.Millisecond); err != nil {
+			return "", err
+		}
+	}
+
+	return "", ErrTimeout
+}
+
+func (t *torBoxService) UnrestrictLink(ctx context.Context, link string) (string, error) {
+	var created struct {
+		WebID int64 `json:"webdownload_id"`
+	}
+	if err := t.postMultipart(ctx, "/webdl/createwebdownload", map[string]string{"link": link}, &created); err != nil {
+		return "", err
+	}
+
+	var direct string
+	values := url.Values{"token": {t.token}, "web_id": {fmt.Sprint(created.WebID)}}
+	if err := t.get(ctx, "/webdl/requestdl", values, &direct); err != nil {
+		return "", err
+	}
+	if direct == "" {
+		return "", errors.New("torbox returned empty download link")
+	}
+	return direct, nil
+}
+
+func (t *torBoxService) CheckCache(ctx context.Context, hashes []string) (map[string]bool, error) {
+	cached := map[string]bool{}
+	hashes = uniqueHashes(hashes)
+	if len(hashes) == 0 {
+		return cached, nil
+	}
+
+	var payload map[string]json.RawMessage
+	values := url.Values{"hash": {strings.Join(hashes, ",")}, "format": {"object"}}
+	if err := t.get(ctx, "/torrents/checkcached", values, &payload); err != nil {
+		return cached, err
+	}
+
+	for hash := range payload {
+		cached[strings.ToLower(hash)] = true
+	}
+	return cached, nil
+}
+
+func (t *torBoxService) get(ctx context.Context, route string, values url.Values, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, torBoxBase+route+"?"+values.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	return t.do(req, out)
+}
+
+func (t *torBoxService) postMultipart(ctx context.Context, route string, fields map[string]string, out any) error {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for key, value := range fields {
+		if err := writer.WriteField(key, value); err != nil {
+			return err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, torBoxBase+route, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return t.do(req, out)
+}
+
+func (t *torBoxService) do(req *http.Request, out any) error {
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := t.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return readDebridError("torbox", resp)
+	}
+
+	var envelope struct {
+		Success bool            `json:"success"`
+		Detail  string          `json:"detail"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return err
+	}
+	if !envelope.Success {
+		return errors.New("torbox: " + envelope.Detail)
+	}
+	if out == nil || len(envelope.Data) == 0 || string(envelope.Data) == "null" {
+		return nil
+	}
+	return json.Unmarshal(envelope.Data, out)
+}
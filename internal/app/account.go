@@ -0,0 +1,163 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"tuiflix/internal/api"
+	"tuiflix/internal/app/components"
+)
+
+// premiumWarnAfter is how close to expiry premium has to be before the
+// status line starts warning about it.
+const premiumWarnAfter = 7 * 24 * time.Hour
+
+type accountInfo struct {
+	account   api.Account
+	traffic   []api.HostTraffic
+	downloads []api.Download
+}
+
+func (m Model) openAccount() (tea.Model, tea.Cmd) {
+	if !m.client.RealDebridEnabled() {
+		m.status = "The account view needs Real-Debrid, press L to log in"
+		return m, nil
+	}
+	m.mode = modeAccount
+	m.setFocus(focusSettings)
+	m.status = "Loading Real-Debrid account..."
+	return m, loadAccountCmd(m.requests.start(requestAccount), m.client)
+}
+
+func (m Model) updateAccountKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "r":
+		m.status = "Refreshing Real-Debrid account..."
+		return m, loadAccountCmd(m.requests.start(requestAccount), m.client)
+	case "esc":
+		m.mode = modeBrowse
+		m.setFocus(focusRight)
+		m.status = "Back to browse"
+	}
+	return m, nil
+}
+
+func premiumWarning(account api.Account) string {
+	if account.Type != "premium" {
+		if !account.Expiration.IsZero() && account.Expiration.Before(time.Now()) {
+			return "Real-Debrid premium has expired"
+		}
+		return "Real-Debrid account is not premium"
+	}
+	left := account.PremiumLeft()
+	if left > premiumWarnAfter {
+		return ""
+	}
+	days := int(left.Hours() / 24)
+	if days < 1 {
+		return "Real-Debrid premium expires today"
+	}
+	return fmt.Sprintf("Real-Debrid premium expires in %d day(s)", days)
+}
+
+func (m *Model) renderAccountPopup(width int, height int) string {
+	popupW := min(width-6, 88)
+	if popupW < 52 {
+		popupW = width - 2
+	}
+	popupH := min(height-4, 26)
+	innerW := popupW - 4
+
+	muted := lipgloss.NewStyle().Foreground(mutedText)
+	heading := lipgloss.NewStyle().Foreground(accentText).Bold(true)
+	content := []string{
+		heading.Render("Real-Debrid Account"),
+		muted.Render("r to refresh, Esc to close"),
+		"",
+	}
+
+	if m.account == nil {
+		content = append(content, muted.Render("Loading..."))
+	} else {
+		account := m.account.account
+		premium := "not premium"
+		if account.Type == "premium" {
+			premium = fmt.Sprintf("%d day(s) left, until %s", int(account.PremiumLeft().Hours()/24), account.Expiration.Local().Format("2006-01-02"))
+		}
+		if warning := premiumWarning(account); warning != "" {
+			premium = lipgloss.NewStyle().Foreground(warningText).Bold(true).Render(premium)
+		}
+
+		label := muted.Width(12)
+		content = append(content,
+			label.Render("User")+account.Username,
+			label.Render("Premium")+premium,
+			label.Render("Points")+fmt.Sprint(account.Points),
+			"",
+		)
+
+		// Traffic and recent downloads share whatever height is left.
+		rows := popupH - len(content) - 4
+		downloadRows := min(len(m.account.downloads), max(rows/3, 1))
+		trafficRows := rows - downloadRows
+
+		content = append(content, heading.Render("Host traffic"))
+		content = append(content, renderTrafficRows(m.account.traffic, trafficRows, innerW)...)
+		content = append(content, heading.Render("Recent downloads"))
+		if len(m.account.downloads) == 0 {
+			content = append(content, muted.Render("none"))
+		}
+		for _, download := range m.account.downloads[:downloadRows] {
+			line := fmt.Sprintf("%-10s %9s  %s", download.Generated.Local().Format("2006-01-02"), components.FormatBytes(download.Filesize), download.Filename)
+			content = append(content, compactText(line, innerW))
+		}
+	}
+
+	style := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(accentText).
+		Padding(0, 1).
+		Width(popupW).
+		Height(popupH)
+
+	return style.Render(strings.Join(content, "\n"))
+}
+
+func renderTrafficRows(traffic []api.HostTraffic, rows int, width int) []string {
+	if rows <= 0 {
+		return nil
+	}
+	if len(traffic) == 0 {
+		return []string{lipgloss.NewStyle().Foreground(mutedText).Render("no limits reported")}
+	}
+
+	shown := traffic
+	if len(shown) > rows {
+		shown = shown[:rows-1]
+	}
+
+	lines := make([]string, 0, rows)
+	for _, host := range shown {
+		limit := "unlimited"
+		if host.Limit > 0 {
+			limit = fmt.Sprintf("%s left of %s", components.FormatBytes(host.Left), components.FormatBytes(host.Limit))
+			if host.Type == "links" {
+				limit = fmt.Sprintf("%d of %d links left", host.Left, host.Limit)
+			}
+		}
+		if host.Reset != "" {
+			limit += ", resets " + host.Reset
+		}
+		lines = append(lines, compactText(fmt.Sprintf("%-22s %s", host.Host, limit), width))
+	}
+	if hidden := len(traffic) - len(shown); hidden > 0 {
+		lines = append(lines, lipgloss.NewStyle().Foreground(mutedText).Render(fmt.Sprintf("+%d more host(s)", hidden)))
+	}
+	return lines
+}
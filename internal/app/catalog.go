@@ -0,0 +1,202 @@
+package app
+
+import (
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"tuiflix/internal/api"
+)
+
+// allGenres is the genre entry that lists a catalog unfiltered.
+const allGenres = "All"
+
+// catalogState holds the catalogs the addons offer and the one shown in the
+// browse panes, with its genre and titles.
+type catalogState struct {
+	catalogs []api.BrowseCatalog
+	current  api.BrowseCatalog
+	genre    string
+	movies   []api.MediaItem
+	shows    []api.MediaItem
+	pages    [2]pager
+}
+
+func (m Model) openCatalogs() (tea.Model, tea.Cmd) {
+	m.mode = modeCatalog
+	m.setFocus(focusCatalogs)
+	if len(m.catalog.catalogs) > 0 {
+		m.syncCatalogLists()
+		m.status = "Pick a catalog and genre, then press Enter"
+		return m, nil
+	}
+	m.status = "Loading catalogs..."
+	return m, loadCatalogsCmd(m.requests.start(requestCatalog), m.client)
+}
+
+func (m Model) updateCatalogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc":
+		m.requests.cancel(requestCatalog)
+		m.mode = modeBrowse
+		m.setFocus(focusRight)
+		m.status = "Back to browse"
+		return m, nil
+	case "tab", "right":
+		m.setFocus(focusGenres)
+		return m, nil
+	case "shift+tab", "left":
+		m.setFocus(focusCatalogs)
+		return m, nil
+	case "enter":
+		catalog, ok := m.pickedCatalog()
+		if !ok {
+			return m, nil
+		}
+		m.catalog.current = catalog
+		m.catalog.genre = m.pickedGenre()
+		m.mode = modeBrowse
+		m.setFocus(focusMovies)
+		m.status = "Loading " + catalogLabel(catalog, m.catalog.genre, "titles") + "..."
+		return m, loadCatalogCmd(m.requests.start(requestCatalog), m.client, catalog, m.catalog.genre)
+	}
+
+	if m.focus == focusGenres {
+		return m, m.genreList.Update(msg)
+	}
+	previous := m.catalogList.Cursor()
+	cmd := m.catalogList.Update(msg)
+	if previous != m.catalogList.Cursor() {
+		m.syncGenreList()
+	}
+	return m, cmd
+}
+
+// syncCatalogLists fills the picker, starting at the catalog and genre the
+// panes show.
+func (m *Model) syncCatalogLists() {
+	names := make([]string, 0, len(m.catalog.catalogs))
+	cursor := 0
+	for idx, catalog := range m.catalog.catalogs {
+		names = append(names, m.catalogName(catalog))
+		if sameCatalog(catalog, m.catalog.current) {
+			cursor = idx
+		}
+	}
+	m.catalogList.SetItems(names)
+	m.catalogList.SetCursor(cursor)
+	m.syncGenreList()
+}
+
+func (m *Model) syncGenreList() {
+	catalog, ok := m.pickedCatalog()
+	if !ok {
+		m.genreList.SetItems(nil)
+		return
+	}
+	genres := catalogGenres(catalog)
+	m.genreList.SetItems(genres)
+	m.genreList.SetCursor(0)
+	if sameCatalog(catalog, m.catalog.current) && m.catalog.genre != "" {
+		for idx, genre := range genres {
+			if genre == m.catalog.genre {
+				m.genreList.SetCursor(idx)
+			}
+		}
+	}
+}
+
+func (m Model) pickedCatalog() (api.BrowseCatalog, bool) {
+	idx := m.catalogList.Cursor()
+	if idx < 0 || idx >= len(m.catalog.catalogs) {
+		return api.BrowseCatalog{}, false
+	}
+	return m.catalog.catalogs[idx], true
+}
+
+func (m Model) pickedGenre() string {
+	genre, ok := m.genreList.Selected()
+	if !ok || genre == allGenres {
+		return ""
+	}
+	return genre
+}
+
+// catalogName names a catalog in the picker, adding the addon once more
+// than one addon offers catalogs.
+func (m Model) catalogName(catalog api.BrowseCatalog) string {
+	for _, other := range m.catalog.catalogs {
+		if other.Addon != catalog.Addon {
+			return catalog.Name + " (" + catalog.Addon + ")"
+		}
+	}
+	return catalog.Name
+}
+
+func catalogGenres(catalog api.BrowseCatalog) []string {
+	if catalog.GenreRequired {
+		return catalog.Genres
+	}
+	return append([]string{allGenres}, catalog.Genres...)
+}
+
+func sameCatalog(a api.BrowseCatalog, b api.BrowseCatalog) bool {
+	return a.ID == b.ID && a.Addon == b.Addon
+}
+
+// catalogLabel titles what catalog lists for genre, such as "Popular Sci-Fi
+// Movies", or "Movies from 1999" when the genres are years.
+func catalogLabel(catalog api.BrowseCatalog, genre string, kind string) string {
+	if genre == "" {
+		return catalog.Name + " " + kind
+	}
+	if isYear(genre) {
+		return strings.ToUpper(kind[:1]) + kind[1:] + " from " + genre
+	}
+	return catalog.Name + " " + genre + " " + kind
+}
+
+func isYear(value string) bool {
+	year, err := strconv.Atoi(value)
+	return err == nil && len(value) == 4 && year > 1800
+}
+
+func (m *Model) renderCatalogPopup(width int, height int) string {
+	popupW := min(width-6, 80)
+	if popupW < 52 {
+		popupW = width - 2
+	}
+	popupH := min(height-4, 24)
+	if popupH < 14 {
+		popupH = 14
+	}
+
+	leftW := (popupW - 2) / 2
+	rightW := popupW - 2 - leftW
+	listsHeight := popupH - 2
+
+	pickers := lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		m.catalogList.View(leftW, listsHeight, m.focus == focusCatalogs),
+		m.genreList.View(rightW, listsHeight, m.focus == focusGenres),
+	)
+
+	content := []string{
+		lipgloss.NewStyle().Foreground(accentText).Bold(true).Render("Browse Catalogs"),
+		lipgloss.NewStyle().Foreground(mutedText).Render(compactText("Tab/Left/Right to switch, arrows to move, Enter to browse, Esc to close", popupW-4)),
+		pickers,
+	}
+
+	style := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(accentText).
+		Padding(0, 1).
+		Width(popupW).
+		Height(popupH)
+
+	return style.Render(strings.Join(content, "\n"))
+}
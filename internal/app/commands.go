@@ -2,12 +2,17 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/atotto/clipboard"
 	tea "github.com/charmbracelet/bubbletea"
 
 	"tuiflix/internal/api"
+	"tuiflix/internal/config"
 	"tuiflix/internal/player"
+	"tuiflix/internal/poster"
+	"tuiflix/internal/store"
 )
 
 type popularLoadedMsg struct {
@@ -22,9 +27,38 @@ type searchLoadedMsg struct {
 	err     error
 }
 
+type catalogsLoadedMsg struct {
+	catalogs []api.BrowseCatalog
+	err      error
+}
+
+type catalogLoadedMsg struct {
+	catalog api.BrowseCatalog
+	genre   string
+	movies  []api.MediaItem
+	shows   []api.MediaItem
+	err     error
+}
+
+type pageLoadedMsg struct {
+	query pageQuery
+	items []api.MediaItem
+	err   error
+}
+
+type searchDebounceMsg struct {
+	query string
+}
+
+type metaLoadedMsg struct {
+	itemID string
+	meta   api.MediaMeta
+	err    error
+}
+
 type episodesLoadedMsg struct {
 	itemID   string
-	bySeason map[int][]int
+	bySeason map[int][]api.Episode
 	err      error
 }
 
@@ -34,8 +68,46 @@ type streamsLoadedMsg struct {
 	err     error
 }
 
+type playRequest struct {
+	item    api.MediaItem
+	season  int
+	episode int
+	stream  api.Stream
+	start   float64
+	// direct skips debrid resolution and plays the stream's own link.
+	direct bool
+}
+
+func (r playRequest) title() string {
+	if r.item.Type == "series" {
+		return fmt.Sprintf("%s S%02dE%02d", r.item.Name, r.season, r.episode)
+	}
+	return r.item.Name
+}
+
+type cacheCheckedMsg struct {
+	key     string
+	streams []api.Stream
+	err     error
+}
+
+func checkCacheCmd(ctx context.Context, client *api.Client, streams []api.Stream, key string) tea.Cmd {
+	return func() tea.Msg {
+		checked, err := client.CheckCached(ctx, streams)
+		return cacheCheckedMsg{key: key, streams: checked, err: err}
+	}
+}
+
 type streamOpenedMsg struct {
-	err error
+	req        playRequest
+	controller *player.Controller
+	err        error
+}
+
+type playbackMsg struct {
+	controller *player.Controller
+	state      player.State
+	closed     bool
 }
 
 func loadPopularCmd(client *api.Client) tea.Cmd {
@@ -48,50 +120,289 @@ func loadPopularCmd(client *api.Client) tea.Cmd {
 	}
 }
 
-func loadSearchCmd(client *api.Client, query string) tea.Cmd {
+func loadSearchCmd(ctx context.Context, client *api.Client, query string) tea.Cmd {
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
-		defer cancel()
-
 		results, err := client.Search(ctx, query)
 		return searchLoadedMsg{query: query, results: results, err: err}
 	}
 }
 
-func loadEpisodesCmd(client *api.Client, id string) tea.Cmd {
+func loadCatalogsCmd(ctx context.Context, client *api.Client) tea.Cmd {
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
-		defer cancel()
+		catalogs, err := client.BrowseCatalogs(ctx)
+		return catalogsLoadedMsg{catalogs: catalogs, err: err}
+	}
+}
 
+func loadCatalogCmd(ctx context.Context, client *api.Client, catalog api.BrowseCatalog, genre string) tea.Cmd {
+	return func() tea.Msg {
+		movies, shows, err := client.FetchCatalog(ctx, catalog, genre)
+		return catalogLoadedMsg{catalog: catalog, genre: genre, movies: movies, shows: shows, err: err}
+	}
+}
+
+func loadPageCmd(ctx context.Context, client *api.Client, query pageQuery) tea.Cmd {
+	return func() tea.Msg {
+		var (
+			items []api.MediaItem
+			err   error
+		)
+		mediaType := query.pane.mediaType()
+		switch query.view {
+		case browseSearch:
+			items, err = client.SearchPage(ctx, mediaType, query.search, query.skip)
+		case browseCatalog:
+			items, err = client.FetchCatalogPage(ctx, query.catalog, mediaType, query.genre, query.skip)
+		default:
+			items, err = client.FetchPopularPage(ctx, mediaType, query.skip)
+		}
+		return pageLoadedMsg{query: query, items: items, err: err}
+	}
+}
+
+// searchDebounce is how long typing has to pause before the search runs.
+const searchDebounce = 250 * time.Millisecond
+
+func searchDebounceCmd(query string) tea.Cmd {
+	return tea.Tick(searchDebounce, func(time.Time) tea.Msg {
+		return searchDebounceMsg{query: query}
+	})
+}
+
+func loadMetaCmd(ctx context.Context, client *api.Client, item api.MediaItem) tea.Cmd {
+	return func() tea.Msg {
+		meta, err := client.FetchMeta(ctx, item)
+		return metaLoadedMsg{itemID: item.ID, meta: meta, err: err}
+	}
+}
+
+func loadEpisodesCmd(ctx context.Context, client *api.Client, id string) tea.Cmd {
+	return func() tea.Msg {
 		bySeason, err := client.FetchSeriesEpisodes(ctx, id)
 		return episodesLoadedMsg{itemID: id, bySeason: bySeason, err: err}
 	}
 }
 
-func loadStreamsCmd(client *api.Client, item api.MediaItem, season int, episode int, key string) tea.Cmd {
+func loadStreamsCmd(ctx context.Context, client *api.Client, item api.MediaItem, season int, episode int, key string) tea.Cmd {
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
-
 		streams, err := client.FetchStreams(ctx, item, season, episode)
 		return streamsLoadedMsg{key: key, streams: streams, err: err}
 	}
 }
 
-func openStreamCmd(client *api.Client, stream api.Stream) tea.Cmd {
+type resolveProgressMsg struct {
+	updates  <-chan api.ResolveProgress
+	progress api.ResolveProgress
+	closed   bool
+}
+
+// openStreamCmd resolves and opens req under ctx, sending each debrid status
+// poll to updates and closing it once the resolve is over.
+func openStreamCmd(ctx context.Context, client *api.Client, p player.Player, req playRequest, updates chan<- api.ResolveProgress) tea.Cmd {
+	return func() tea.Msg {
+		ctx = api.WithProgress(ctx, func(progress api.ResolveProgress) {
+			select {
+			case updates <- progress:
+			default:
+			}
+		})
+		playableURL, err := resolvePlayable(ctx, client, req)
+		close(updates)
+		if err != nil {
+			return streamOpenedMsg{req: req, err: err}
+		}
+
+		opts := player.Options{Title: req.title(), Start: req.start}
+		if controllable, ok := p.(player.Controllable); ok {
+			controller, err := controllable.Launch(playableURL, opts)
+			if err != nil {
+				return streamOpenedMsg{req: req, err: err}
+			}
+			return streamOpenedMsg{req: req, controller: controller}
+		}
+
+		if err := p.Open(playableURL, opts); err != nil {
+			return streamOpenedMsg{req: req, err: err}
+		}
+
+		return streamOpenedMsg{req: req}
+	}
+}
+
+func resolvePlayable(ctx context.Context, client *api.Client, req playRequest) (string, error) {
+	if req.direct {
+		return api.DirectURL(req.stream)
+	}
+	return client.ResolvePlayableURL(ctx, req.stream)
+}
+
+func waitResolveProgressCmd(updates <-chan api.ResolveProgress) tea.Cmd {
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
-		defer cancel()
+		progress, ok := <-updates
+		return resolveProgressMsg{updates: updates, progress: progress, closed: !ok}
+	}
+}
 
-		playableURL, err := client.ResolvePlayableURL(ctx, stream)
+func waitPlaybackCmd(controller *player.Controller) tea.Cmd {
+	return func() tea.Msg {
+		state, ok := <-controller.Updates()
+		return playbackMsg{controller: controller, state: state, closed: !ok}
+	}
+}
+
+func playbackCommandCmd(action func() error) tea.Cmd {
+	return func() tea.Msg {
+		if err := action(); err != nil {
+			return playbackErrMsg{err: err}
+		}
+		return nil
+	}
+}
+
+type playbackErrMsg struct {
+	err error
+}
+
+type historySavedMsg struct {
+	err error
+}
+
+func saveHistoryCmd(history *store.History) tea.Cmd {
+	return func() tea.Msg {
+		return historySavedMsg{err: history.Save()}
+	}
+}
+
+type watchlistSavedMsg struct {
+	err error
+}
+
+func saveWatchlistCmd(watchlist *store.Watchlist) tea.Cmd {
+	return func() tea.Msg {
+		return watchlistSavedMsg{err: watchlist.Save()}
+	}
+}
+
+type configSavedMsg struct {
+	err error
+}
+
+func saveConfigCmd(cfg config.Config) tea.Cmd {
+	return func() tea.Msg {
+		return configSavedMsg{err: cfg.Save()}
+	}
+}
+
+type loginStartedMsg struct {
+	code api.DeviceCode
+	err  error
+}
+
+type loginDoneMsg struct {
+	client *api.Client
+	err    error
+}
+
+func startLoginCmd(ctx context.Context) tea.Cmd {
+	return func() tea.Msg {
+		code, err := api.NewRealDebridOAuth("").StartDevice(ctx)
+		return loginStartedMsg{code: code, err: err}
+	}
+}
+
+// waitLoginCmd waits for the user to approve code, stores the token and
+// builds a client that uses it.
+func waitLoginCmd(ctx context.Context, cfg config.Config, code api.DeviceCode) tea.Cmd {
+	return func() tea.Msg {
+		token, err := api.NewRealDebridOAuth("").WaitForToken(ctx, code)
+		if err != nil {
+			return loginDoneMsg{err: err}
+		}
+		if err := config.SaveRealDebridLogin(token); err != nil {
+			return loginDoneMsg{err: err}
+		}
+		client, err := api.NewClient(cfg.ClientConfig())
+		return loginDoneMsg{client: client, err: err}
+	}
+}
+
+// startAccountMsg asks Update to load the Real-Debrid account at startup.
+type startAccountMsg struct{}
+
+type accountLoadedMsg struct {
+	info accountInfo
+	err  error
+}
+
+func loadAccountCmd(ctx context.Context, client *api.Client) tea.Cmd {
+	return func() tea.Msg {
+		account, traffic, err := client.RealDebridAccount(ctx)
+		if err != nil {
+			return accountLoadedMsg{err: err}
+		}
+		downloads, err := client.RealDebridDownloads(ctx, 10)
+		return accountLoadedMsg{info: accountInfo{account: account, traffic: traffic, downloads: downloads}, err: err}
+	}
+}
+
+type libraryLoadedMsg struct {
+	torrents  []api.Torrent
+	downloads []api.Download
+	err       error
+}
+
+type torrentFilesLoadedMsg struct {
+	torrentID string
+	files     []api.TorrentFile
+	err       error
+}
+
+type linkCopiedMsg struct {
+	link string
+	err  error
+}
+
+func loadLibraryCmd(ctx context.Context, client *api.Client) tea.Cmd {
+	return func() tea.Msg {
+		torrents, err := client.RealDebridTorrents(ctx)
 		if err != nil {
-			return streamOpenedMsg{err: err}
+			return libraryLoadedMsg{err: err}
 		}
+		downloads, err := client.RealDebridDownloads(ctx, 100)
+		return libraryLoadedMsg{torrents: torrents, downloads: downloads, err: err}
+	}
+}
 
-		if err := player.OpenIINA(playableURL); err != nil {
-			return streamOpenedMsg{err: err}
+func loadTorrentFilesCmd(ctx context.Context, client *api.Client, id string) tea.Cmd {
+	return func() tea.Msg {
+		files, err := client.RealDebridTorrentFiles(ctx, id)
+		return torrentFilesLoadedMsg{torrentID: id, files: files, err: err}
+	}
+}
+
+// copyLinkCmd puts link on the clipboard, unrestricting it first when it is
+// a hoster link.
+func copyLinkCmd(ctx context.Context, client *api.Client, link string, unrestrict bool) tea.Cmd {
+	return func() tea.Msg {
+		if unrestrict {
+			direct, err := client.UnrestrictLink(ctx, link)
+			if err != nil {
+				return linkCopiedMsg{err: err}
+			}
+			link = direct
 		}
+		return linkCopiedMsg{link: link, err: clipboard.WriteAll(link)}
+	}
+}
 
-		return streamOpenedMsg{}
+type posterLoadedMsg struct {
+	url string
+	err error
+}
+
+func loadPosterCmd(ctx context.Context, cache *poster.Cache, url string) tea.Cmd {
+	return func() tea.Msg {
+		_, err := cache.Fetch(ctx, url)
+		return posterLoadedMsg{url: url, err: err}
 	}
 }
@@ -0,0 +1,125 @@
+package components
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"tuiflix/internal/api"
+)
+
+type episodeItem struct {
+	episode api.Episode
+	aired   bool
+}
+
+func (i episodeItem) Title() string {
+	parts := []string{fmt.Sprintf("%d", i.episode.Number)}
+	if i.episode.Name != "" {
+		parts = append(parts, i.episode.Name)
+	}
+	if !i.episode.Released.IsZero() {
+		parts = append(parts, i.episode.Released.UTC().Format("2006-01-02"))
+	}
+	return strings.Join(parts, " · ")
+}
+
+func (i episodeItem) Description() string {
+	return ""
+}
+
+func (i episodeItem) FilterValue() string {
+	return i.episode.Name
+}
+
+// episodeDelegate renders episodes that have not aired yet with dimmed
+// styles.
+type episodeDelegate struct {
+	list.DefaultDelegate
+	unaired list.DefaultDelegate
+}
+
+func (d episodeDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	if episode, ok := item.(episodeItem); ok && !episode.aired {
+		d.unaired.Render(w, m, index, item)
+		return
+	}
+	d.DefaultDelegate.Render(w, m, index, item)
+}
+
+type EpisodeList struct {
+	list list.Model
+}
+
+func NewEpisodeList(title string) EpisodeList {
+	delegate := list.NewDefaultDelegate()
+	delegate.ShowDescription = false
+	delegate.SetSpacing(0)
+
+	styles := list.NewDefaultItemStyles()
+	styles.NormalTitle = styles.NormalTitle.Foreground(lipgloss.Color("252"))
+	styles.SelectedTitle = styles.SelectedTitle.Foreground(accentColor).Bold(true)
+	delegate.Styles = styles
+
+	unaired := delegate
+	unaired.Styles.NormalTitle = unaired.Styles.NormalTitle.Foreground(lipgloss.Color("240"))
+	unaired.Styles.SelectedTitle = unaired.Styles.SelectedTitle.Foreground(mutedColor)
+
+	lm := newBaseList(title, episodeDelegate{DefaultDelegate: delegate, unaired: unaired})
+	lm.SetShowPagination(false)
+
+	return EpisodeList{list: lm}
+}
+
+func (e *EpisodeList) SetItems(episodes []api.Episode) {
+	current := clamp(e.list.Index(), len(episodes))
+	now := time.Now()
+
+	mapped := make([]list.Item, 0, len(episodes))
+	for _, episode := range episodes {
+		mapped = append(mapped, episodeItem{episode: episode, aired: episode.Aired(now)})
+	}
+
+	e.list.SetItems(mapped)
+	if len(mapped) > 0 {
+		e.list.Select(current)
+	} else {
+		e.list.ResetSelected()
+	}
+}
+
+func (e *EpisodeList) SetCursor(index int) {
+	if len(e.list.Items()) == 0 {
+		e.list.ResetSelected()
+		return
+	}
+	e.list.Select(clamp(index, len(e.list.Items())))
+}
+
+func (e EpisodeList) Cursor() int {
+	return e.list.Index()
+}
+
+func (e EpisodeList) Selected() (api.Episode, bool) {
+	selected, ok := e.list.SelectedItem().(episodeItem)
+	if !ok {
+		return api.Episode{}, false
+	}
+	return selected.episode, true
+}
+
+func (e *EpisodeList) Update(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	e.list, cmd = e.list.Update(msg)
+	return cmd
+}
+
+func (e *EpisodeList) View(width int, height int, focused bool) string {
+	e.list.SetSize(width-2, height-2)
+	return renderPane(e.list.View(), width, height, focused)
+}
@@ -0,0 +1,110 @@
+package components
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// LibraryEntry is one row of the debrid library: a torrent, one of its
+// files, or a download.
+type LibraryEntry struct {
+	ID     string
+	Name   string
+	Size   int64
+	Status string
+	Date   time.Time
+	Link   string
+}
+
+type libraryListItem struct {
+	entry LibraryEntry
+}
+
+func (i libraryListItem) Title() string {
+	return i.entry.Name
+}
+
+func (i libraryListItem) Description() string {
+	date := "-"
+	if !i.entry.Date.IsZero() {
+		date = i.entry.Date.Local().Format("2006-01-02")
+	}
+	columns := []string{
+		padLeft(FormatBytes(i.entry.Size), 9),
+		pad(orDash(i.entry.Status), 24),
+		date,
+	}
+	return strings.Join(columns, "  ")
+}
+
+func (i libraryListItem) FilterValue() string {
+	return i.entry.Name
+}
+
+type LibraryList struct {
+	list list.Model
+}
+
+func NewLibraryList(title string) LibraryList {
+	delegate := list.NewDefaultDelegate()
+	delegate.ShowDescription = true
+	delegate.SetSpacing(0)
+
+	styles := list.NewDefaultItemStyles()
+	styles.NormalTitle = styles.NormalTitle.Foreground(lipgloss.Color("252"))
+	styles.NormalDesc = styles.NormalDesc.Foreground(mutedColor)
+	styles.SelectedTitle = styles.SelectedTitle.Foreground(accentColor).Bold(true)
+	styles.SelectedDesc = styles.SelectedDesc.Foreground(accentColor)
+	delegate.Styles = styles
+
+	return LibraryList{list: newBaseList(title, delegate)}
+}
+
+func (l *LibraryList) SetTitle(title string) {
+	l.list.Title = title
+}
+
+func (l *LibraryList) SetItems(entries []LibraryEntry) {
+	mapped := make([]list.Item, 0, len(entries))
+	for _, entry := range entries {
+		mapped = append(mapped, libraryListItem{entry: entry})
+	}
+
+	l.list.SetItems(mapped)
+	l.list.ResetSelected()
+}
+
+func (l *LibraryList) SetCursor(index int) {
+	if len(l.list.Items()) == 0 {
+		l.list.ResetSelected()
+		return
+	}
+	l.list.Select(clamp(index, len(l.list.Items())))
+}
+
+func (l LibraryList) Cursor() int {
+	return l.list.Index()
+}
+
+func (l LibraryList) Selected() (LibraryEntry, bool) {
+	selected, ok := l.list.SelectedItem().(libraryListItem)
+	if !ok {
+		return LibraryEntry{}, false
+	}
+	return selected.entry, true
+}
+
+func (l *LibraryList) Update(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	l.list, cmd = l.list.Update(msg)
+	return cmd
+}
+
+func (l *LibraryList) View(width int, height int, focused bool) string {
+	l.list.SetSize(width-2, height-2)
+	return renderPane(l.list.View(), width, height, focused)
+}
@@ -2,6 +2,7 @@ package components
 
 import (
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/list"
@@ -12,7 +13,9 @@ import (
 )
 
 type mediaListItem struct {
-	item api.MediaItem
+	item   api.MediaItem
+	note   string
+	marked bool
 }
 
 func (i mediaListItem) Title() string {
@@ -25,7 +28,13 @@ func (i mediaListItem) Description() string {
 		kind = "Series"
 	}
 	if i.item.Year > 0 {
-		return fmt.Sprintf("%s | %d", kind, i.item.Year)
+		kind = fmt.Sprintf("%s | %d", kind, i.item.Year)
+	}
+	if i.note != "" {
+		kind += " | " + i.note
+	}
+	if i.marked {
+		kind = "★ " + kind
 	}
 	return kind
 }
@@ -34,8 +43,40 @@ func (i mediaListItem) FilterValue() string {
 	return strings.TrimSpace(i.item.Name)
 }
 
+// loadingItem is the row at the end of a list while its next page loads.
+type loadingItem struct{}
+
+func (loadingItem) Title() string {
+	return "Loading more..."
+}
+
+func (loadingItem) Description() string {
+	return ""
+}
+
+func (loadingItem) FilterValue() string {
+	return ""
+}
+
+// mediaDelegate renders the loading row with dimmed styles.
+type mediaDelegate struct {
+	list.DefaultDelegate
+	loading list.DefaultDelegate
+}
+
+func (d mediaDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	if _, ok := item.(loadingItem); ok {
+		d.loading.Render(w, m, index, item)
+		return
+	}
+	d.DefaultDelegate.Render(w, m, index, item)
+}
+
 type MediaList struct {
-	list list.Model
+	list    list.Model
+	notes   map[string]string
+	marked  map[string]bool
+	loading bool
 }
 
 func NewMediaList(title string) MediaList {
@@ -50,35 +91,71 @@ func NewMediaList(title string) MediaList {
 	styles.SelectedDesc = styles.SelectedDesc.Foreground(accentColor)
 	delegate.Styles = styles
 
-	return MediaList{list: newBaseList(title, delegate)}
+	loading := delegate
+	loading.Styles.NormalTitle = loading.Styles.NormalTitle.Foreground(lipgloss.Color("240")).Italic(true)
+	loading.Styles.SelectedTitle = loading.Styles.SelectedTitle.Foreground(mutedColor).Italic(true)
+
+	return MediaList{list: newBaseList(title, mediaDelegate{DefaultDelegate: delegate, loading: loading})}
 }
 
 func (m *MediaList) SetTitle(title string) {
 	m.list.Title = title
 }
 
+// SetItems replaces the list's items, keeping the cursor at the same index
+// so appending a page does not move it.
 func (m *MediaList) SetItems(items []api.MediaItem) {
-	current := clamp(m.list.Index(), len(items))
-
-	mapped := make([]list.Item, 0, len(items))
+	mapped := make([]list.Item, 0, len(items)+1)
 	for _, item := range items {
-		mapped = append(mapped, mediaListItem{item: item})
+		mapped = append(mapped, mediaListItem{item: item, note: m.notes[item.ID], marked: m.marked[item.ID]})
+	}
+	if m.loading {
+		mapped = append(mapped, loadingItem{})
 	}
+	current := clamp(m.list.Index(), len(mapped))
 
 	m.list.SetItems(mapped)
 	if len(mapped) > 0 {
 		m.list.Select(current)
+		m.skipLoadingRow()
 	} else {
 		m.list.ResetSelected()
 	}
 }
 
+// skipLoadingRow moves the cursor off the loading row onto the last title,
+// since the row stands for no title.
+func (m *MediaList) skipLoadingRow() {
+	if _, ok := m.list.SelectedItem().(loadingItem); ok && m.list.Index() > 0 {
+		m.list.Select(m.list.Index() - 1)
+	}
+}
+
+// SetNotes attaches an extra description per item ID. It applies to the
+// items passed to the next SetItems call.
+func (m *MediaList) SetNotes(notes map[string]string) {
+	m.notes = notes
+}
+
+// SetLoading adds a loading row below the items while the next page is
+// fetched. Like SetNotes it applies on the next SetItems.
+func (m *MediaList) SetLoading(loading bool) {
+	m.loading = loading
+}
+
+// SetMarked flags item IDs that get a star in their description, such as
+// titles on the watchlist. Like SetNotes it applies on the next SetItems.
+func (m *MediaList) SetMarked(ids map[string]bool) {
+	m.marked = ids
+}
+
 func (m *MediaList) SetCursor(index int) {
 	if len(m.list.Items()) == 0 {
 		m.list.ResetSelected()
 		return
 	}
 	m.list.Select(clamp(index, len(m.list.Items())))
+	m.skipLoadingRow()
 }
 
 func (m MediaList) Cursor() int {
@@ -96,6 +173,7 @@ func (m MediaList) Selected() (api.MediaItem, bool) {
 func (m *MediaList) Update(msg tea.Msg) tea.Cmd {
 	var cmd tea.Cmd
 	m.list, cmd = m.list.Update(msg)
+	m.skipLoadingRow()
 	return cmd
 }
 
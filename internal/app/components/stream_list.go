@@ -1,6 +1,7 @@
 package components
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/list"
@@ -15,6 +16,39 @@ type streamListItem struct {
 }
 
 func (i streamListItem) Title() string {
+	marker := "  "
+	if i.stream.Cached {
+		marker = "⚡"
+	}
+
+	info := i.stream.Info
+	if info.Resolution == "" && info.Size == 0 && info.Seeders == 0 {
+		return marker + " " + i.rawTitle()
+	}
+
+	flags := make([]string, 0, 2)
+	if info.DolbyVision {
+		flags = append(flags, "DV")
+	}
+	if info.HDR {
+		flags = append(flags, "HDR")
+	}
+
+	columns := []string{
+		pad(orDash(info.Resolution), 6),
+		pad(orDash(info.Source), 7),
+		pad(orDash(info.Codec), 5),
+		pad(strings.Join(flags, " "), 6),
+		pad(info.Audio, 9),
+		padLeft(FormatBytes(info.Size), 9),
+		padLeft(fmt.Sprintf("%d seeds", info.Seeders), 10),
+		pad(compact(info.Provider, 14), 14),
+		strings.Join(info.Languages, " "),
+	}
+	return marker + " " + strings.TrimRight(strings.Join(columns, " "), " ")
+}
+
+func (i streamListItem) rawTitle() string {
 	base := strings.TrimSpace(i.stream.Title)
 	if base == "" {
 		base = strings.TrimSpace(i.stream.Name)
@@ -31,10 +65,17 @@ func (i streamListItem) Description() string {
 	if provider == "" {
 		provider = "unknown"
 	}
+	provider = strings.ReplaceAll(provider, "\n", " ")
+	if i.stream.Addon != "" && !strings.Contains(provider, i.stream.Addon) {
+		provider = i.stream.Addon + " | " + provider
+	}
 	kind := "Magnet"
 	if strings.HasPrefix(strings.ToLower(i.stream.URL), "http") {
 		kind = "HTTP"
 	}
+	if i.stream.Info.Filename != "" {
+		return i.stream.Info.Filename + " | " + provider + " | " + kind
+	}
 	return provider + " | " + kind
 }
 
@@ -42,6 +83,46 @@ func (i streamListItem) FilterValue() string {
 	return i.Title()
 }
 
+func pad(value string, width int) string {
+	return fmt.Sprintf("%-*s", width, value)
+}
+
+func padLeft(value string, width int) string {
+	return fmt.Sprintf("%*s", width, value)
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+func compact(value string, width int) string {
+	runes := []rune(value)
+	if len(runes) <= width {
+		return value
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+func FormatBytes(size int64) string {
+	if size <= 0 {
+		return "-"
+	}
+	units := []string{"B", "KB", "MB", "GB", "TB"}
+	value := float64(size)
+	unit := 0
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	if unit < 3 {
+		return fmt.Sprintf("%.0f %s", value, units[unit])
+	}
+	return fmt.Sprintf("%.1f %s", value, units[unit])
+}
+
 type StreamList struct {
 	list list.Model
 }
@@ -81,6 +162,28 @@ func (s *StreamList) SetItems(items []api.Stream) {
 	}
 }
 
+// SelectMatching moves the cursor back to stream after the items were
+// replaced, matching on info hash, file index and URL.
+func (s *StreamList) SelectMatching(stream api.Stream) {
+	for idx, item := range s.list.Items() {
+		candidate, ok := item.(streamListItem)
+		if !ok {
+			continue
+		}
+		if candidate.stream.InfoHash == stream.InfoHash && candidate.stream.URL == stream.URL && sameFileIdx(candidate.stream.FileIdx, stream.FileIdx) {
+			s.list.Select(idx)
+			return
+		}
+	}
+}
+
+func sameFileIdx(a *int, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
 func (s *StreamList) SetCursor(index int) {
 	if len(s.list.Items()) == 0 {
 		s.list.ResetSelected()
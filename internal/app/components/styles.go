@@ -40,11 +40,13 @@ func renderPane(content string, width int, height int, focused bool) string {
 		height = 3
 	}
 
+	// Width and Height exclude the border, so take it off to keep the pane
+	// at width x height cells.
 	style := lipgloss.NewStyle().
 		Border(lipgloss.NormalBorder()).
 		BorderForeground(blurBorder).
-		Width(width).
-		Height(height)
+		Width(width - 2).
+		Height(height - 2)
 
 	if focused {
 		style = style.BorderForeground(focusBorder)
@@ -0,0 +1,96 @@
+package components
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type textItem struct {
+	value string
+}
+
+func (i textItem) Title() string {
+	return i.value
+}
+
+func (i textItem) Description() string {
+	return ""
+}
+
+func (i textItem) FilterValue() string {
+	return i.value
+}
+
+// TextList is a single-column list of plain strings, such as catalog names
+// or genres.
+type TextList struct {
+	list list.Model
+}
+
+func NewTextList(title string) TextList {
+	delegate := list.NewDefaultDelegate()
+	delegate.ShowDescription = false
+	delegate.SetSpacing(0)
+
+	styles := list.NewDefaultItemStyles()
+	styles.NormalTitle = styles.NormalTitle.Foreground(lipgloss.Color("252"))
+	styles.SelectedTitle = styles.SelectedTitle.Foreground(accentColor).Bold(true)
+	delegate.Styles = styles
+
+	lm := newBaseList(title, delegate)
+	lm.SetShowPagination(false)
+
+	return TextList{list: lm}
+}
+
+func (t *TextList) SetTitle(title string) {
+	t.list.Title = title
+}
+
+func (t *TextList) SetItems(values []string) {
+	current := clamp(t.list.Index(), len(values))
+
+	mapped := make([]list.Item, 0, len(values))
+	for _, value := range values {
+		mapped = append(mapped, textItem{value: value})
+	}
+
+	t.list.SetItems(mapped)
+	if len(mapped) > 0 {
+		t.list.Select(current)
+	} else {
+		t.list.ResetSelected()
+	}
+}
+
+func (t *TextList) SetCursor(index int) {
+	if len(t.list.Items()) == 0 {
+		t.list.ResetSelected()
+		return
+	}
+	t.list.Select(clamp(index, len(t.list.Items())))
+}
+
+func (t TextList) Cursor() int {
+	return t.list.Index()
+}
+
+func (t TextList) Selected() (string, bool) {
+	selected, ok := t.list.SelectedItem().(textItem)
+	if !ok {
+		return "", false
+	}
+	return selected.value, true
+}
+
+func (t *TextList) Update(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	t.list, cmd = t.list.Update(msg)
+	return cmd
+}
+
+func (t *TextList) View(width int, height int, focused bool) string {
+	t.list.SetSize(width-2, height-2)
+	return renderPane(t.list.View(), width, height, focused)
+}
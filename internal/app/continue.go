@@ -0,0 +1,85 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"tuiflix/internal/api"
+	"tuiflix/internal/store"
+)
+
+// continueTarget is the episode Enter should jump to from the Continue
+// Watching pane. When advance is set the stored episode was finished and the
+// next one is resolved once the season metadata has loaded.
+type continueTarget struct {
+	season  int
+	episode int
+	advance bool
+}
+
+func buildContinueWatching(entries []store.HistoryEntry) ([]api.MediaItem, []api.MediaItem, map[string]continueTarget, map[string]string) {
+	movies := make([]api.MediaItem, 0)
+	shows := make([]api.MediaItem, 0)
+	targets := map[string]continueTarget{}
+	notes := map[string]string{}
+	seen := map[string]struct{}{}
+
+	for _, entry := range entries {
+		id := entry.Item.ID
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+
+		if entry.Item.Type == "series" {
+			target := continueTarget{season: entry.Season, episode: entry.Episode, advance: entry.Finished}
+			targets[id] = target
+			shows = append(shows, entry.Item)
+			if target.advance {
+				notes[id] = fmt.Sprintf("Up next after S%02dE%02d", entry.Season, entry.Episode)
+			} else {
+				notes[id] = fmt.Sprintf("S%02dE%02d%s", entry.Season, entry.Episode, progressNote(entry))
+			}
+			continue
+		}
+
+		if entry.Finished || entry.Position <= 0 {
+			continue
+		}
+		movies = append(movies, entry.Item)
+		notes[id] = "Resume" + progressNote(entry)
+	}
+
+	return movies, shows, targets, notes
+}
+
+func progressNote(entry store.HistoryEntry) string {
+	if entry.Position <= 0 {
+		return ""
+	}
+	if entry.Duration > 0 {
+		return fmt.Sprintf(" at %s (%d%%)", formatClock(entry.Position), int(entry.Position*100/entry.Duration))
+	}
+	return " at " + formatClock(entry.Position)
+}
+
+// nextEpisode returns the first aired episode after season/episode, rolling
+// over into the following seasons. Episodes without an air date or airing
+// after now are skipped, since they have no streams yet. ok is false when
+// the series has nothing aired after it.
+func nextEpisode(bySeason map[int][]api.Episode, season int, episode int, now time.Time) (int, int, bool) {
+	for _, s := range sortedMapKeys(bySeason) {
+		if s < season {
+			continue
+		}
+		for _, candidate := range bySeason[s] {
+			if s == season && candidate.Number <= episode {
+				continue
+			}
+			if !candidate.Released.IsZero() && candidate.Aired(now) {
+				return s, candidate.Number, true
+			}
+		}
+	}
+	return season, episode, false
+}
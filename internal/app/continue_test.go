@@ -0,0 +1,43 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"tuiflix/internal/api"
+)
+
+func TestNextEpisode(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	aired := now.Add(-24 * time.Hour)
+	upcoming := now.Add(7 * 24 * time.Hour)
+	bySeason := map[int][]api.Episode{
+		1: {
+			{Season: 1, Number: 1, Released: aired},
+			{Season: 1, Number: 2, Released: aired},
+		},
+		2: {
+			{Season: 2, Number: 1, Released: aired},
+			{Season: 2, Number: 2, Released: upcoming},
+			{Season: 2, Number: 3},
+		},
+	}
+
+	cases := []struct {
+		name            string
+		season, episode int
+		wantSeason      int
+		wantEpisode     int
+		wantOK          bool
+	}{
+		{name: "same season", season: 1, episode: 1, wantSeason: 1, wantEpisode: 2, wantOK: true},
+		{name: "next season", season: 1, episode: 2, wantSeason: 2, wantEpisode: 1, wantOK: true},
+		{name: "unaired and undated", season: 2, episode: 1, wantSeason: 2, wantEpisode: 1, wantOK: false},
+	}
+	for _, tc := range cases {
+		season, episode, ok := nextEpisode(bySeason, tc.season, tc.episode, now)
+		if season != tc.wantSeason || episode != tc.wantEpisode || ok != tc.wantOK {
+			t.Errorf("%s: got S%dE%d ok=%v, want S%dE%d ok=%v", tc.name, season, episode, ok, tc.wantSeason, tc.wantEpisode, tc.wantOK)
+		}
+	}
+}
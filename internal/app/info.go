@@ -0,0 +1,174 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"tuiflix/internal/api"
+)
+
+// showInfo returns to the details pane of the selected title.
+func (m Model) showInfo() (tea.Model, tea.Cmd) {
+	m.popup = popupInfo
+	m.setFocus(focusStreams)
+	m.status = m.infoStatus()
+	return m, nil
+}
+
+func (m Model) infoStatus() string {
+	if m.selected.Type == "series" {
+		return "Enter picks an episode, Esc goes back"
+	}
+	return "Enter shows streams, Esc goes back"
+}
+
+func (m Model) updateInfoPopupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		if m.resolving != nil {
+			m.cancelResolve()
+			m.status = "Cancelled opening the trailer"
+			return m, nil
+		}
+		return m.closeDetail()
+	case "enter":
+		if m.selected.Type == "series" {
+			m.popup = popupSeasonEpisode
+			m.setFocus(focusSeason)
+			m.status = "Pick season/episode, then press Enter"
+			return m, nil
+		}
+		m.popup = popupStreams
+		m.setFocus(focusStreams)
+		return m, m.reloadStreamsCmd()
+	case "t":
+		if m.meta == nil || len(m.meta.Trailers) == 0 {
+			m.status = "No trailer available"
+			return m, nil
+		}
+		trailer := m.meta.Trailers[0]
+		m.status = "Opening trailer in " + m.player.Name() + "..."
+		return m, m.startResolve(playRequest{
+			item:   api.MediaItem{Name: m.selected.Name + " (trailer)", Type: "movie"},
+			stream: api.Stream{Name: trailer.Title, URL: trailer.URL()},
+			direct: true,
+		})
+	}
+	return m, nil
+}
+
+func (m *Model) renderInfoPopup(width int, height int) string {
+	popupW := min(width-6, 96)
+	if popupW < 56 {
+		popupW = width - 2
+	}
+	popupH := min(height-4, 24)
+	if popupH < 14 {
+		popupH = 14
+	}
+	innerW := popupW - 4
+
+	// The poster takes the left of the popup when there is one, the
+	// details wrap beside it.
+	posterCols := min(innerW/3, popupH*4/3)
+	posterLines, withPoster := m.posterBox(m.detailPoster(), posterCols, min(popupH, posterRows(posterCols)))
+	if withPoster {
+		innerW -= posterCols + 2
+	}
+
+	muted := lipgloss.NewStyle().Foreground(mutedText)
+	instructions := "Enter for streams, t trailer, Esc to close"
+	if m.selected.Type == "series" {
+		instructions = "Enter to pick an episode, t trailer, Esc to close"
+	}
+
+	title := m.selected.Name
+	if m.selected.Year > 0 {
+		title += fmt.Sprintf(" (%d)", m.selected.Year)
+	}
+
+	content := []string{
+		lipgloss.NewStyle().Foreground(accentText).Bold(true).Render(compactText(title, innerW)),
+		muted.Render(compactText(instructions, innerW)),
+		"",
+	}
+
+	switch {
+	case m.meta != nil:
+		content = append(content, renderMetaLines(*m.meta, innerW, popupH-len(content)-2)...)
+	case m.metaErr != nil:
+		content = append(content, muted.Render("Details unavailable"))
+	default:
+		content = append(content, muted.Render("Loading details..."))
+	}
+
+	body := strings.Join(content, "\n")
+	if withPoster {
+		body = lipgloss.JoinHorizontal(lipgloss.Top, strings.Join(posterLines, "\n"), "  ", body)
+	}
+
+	style := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(accentText).
+		Padding(0, 1).
+		Width(popupW).
+		Height(popupH)
+
+	return style.Render(body)
+}
+
+func renderMetaLines(meta api.MediaMeta, width int, height int) []string {
+	muted := lipgloss.NewStyle().Foreground(mutedText)
+	label := muted.Width(10)
+
+	facts := []string{}
+	if len(meta.Genres) > 0 {
+		facts = append(facts, strings.Join(meta.Genres, ", "))
+	}
+	if meta.Runtime != "" {
+		facts = append(facts, meta.Runtime)
+	}
+	if meta.IMDbRating != "" {
+		facts = append(facts, "IMDb "+meta.IMDbRating)
+	}
+	switch {
+	case !meta.Released.IsZero():
+		facts = append(facts, meta.Released.Format("2006-01-02"))
+	case meta.ReleaseInfo != "":
+		facts = append(facts, meta.ReleaseInfo)
+	}
+
+	lines := []string{}
+	if len(facts) > 0 {
+		lines = append(lines, lipgloss.NewStyle().Foreground(accentText).Render(compactText(strings.Join(facts, " · "), width)))
+	}
+	if len(meta.Director) > 0 {
+		lines = append(lines, label.Render("Director")+compactText(strings.Join(meta.Director, ", "), width-10))
+	}
+	if len(meta.Cast) > 0 {
+		lines = append(lines, label.Render("Cast")+compactText(strings.Join(meta.Cast, ", "), width-10))
+	}
+	if len(meta.Trailers) > 0 {
+		lines = append(lines, label.Render("Trailers")+fmt.Sprintf("%d, press t to play", len(meta.Trailers)))
+	}
+
+	if meta.Description != "" {
+		lines = append(lines, "")
+		lines = append(lines, wrapText(meta.Description, width, height-len(lines))...)
+	}
+	return lines
+}
+
+// wrapText wraps text to width and cuts it to maxLines, ending the last line
+// with an ellipsis when something was cut.
+func wrapText(text string, width int, maxLines int) []string {
+	wrapped := strings.Split(lipgloss.NewStyle().Width(width).Render(text), "\n")
+	if len(wrapped) > maxLines && maxLines > 0 {
+		wrapped = wrapped[:maxLines]
+		wrapped[maxLines-1] = compactText(strings.TrimRight(wrapped[maxLines-1], " ")+" …", width)
+	}
+	return wrapped
+}
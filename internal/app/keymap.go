@@ -13,6 +13,17 @@ type keyMap struct {
 	help       key.Binding
 	quit       key.Binding
 	detailPane key.Binding
+	playback   key.Binding
+	resume     key.Binding
+	watchlist  key.Binding
+	watchView  key.Binding
+	catalogs   key.Binding
+	settings   key.Binding
+	login      key.Binding
+	account    key.Binding
+	library    key.Binding
+	sortBy     key.Binding
+	filter     key.Binding
 }
 
 func newKeyMap() keyMap {
@@ -49,6 +60,50 @@ func newKeyMap() keyMap {
 			key.WithKeys("left", "right"),
 			key.WithHelp("left/right", "season/episode"),
 		),
+		resume: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "continue watching"),
+		),
+		watchlist: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "add/remove watchlist"),
+		),
+		watchView: key.NewBinding(
+			key.WithKeys("W"),
+			key.WithHelp("W", "show watchlist"),
+		),
+		catalogs: key.NewBinding(
+			key.WithKeys("C"),
+			key.WithHelp("C", "catalogs/genres"),
+		),
+		settings: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("S", "settings"),
+		),
+		login: key.NewBinding(
+			key.WithKeys("L"),
+			key.WithHelp("L", "real-debrid login"),
+		),
+		account: key.NewBinding(
+			key.WithKeys("A"),
+			key.WithHelp("A", "real-debrid account"),
+		),
+		library: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "real-debrid library"),
+		),
+		sortBy: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "sort streams"),
+		),
+		filter: key.NewBinding(
+			key.WithKeys("4", "c", "m", "d"),
+			key.WithHelp("4/c/m/d", "hide 4K/CAM, max size, cached"),
+		),
+		playback: key.NewBinding(
+			key.WithKeys("p", "[", "]", "x"),
+			key.WithHelp("p/[/]/x", "pause/seek/stop"),
+		),
 	}
 }
 
@@ -60,6 +115,8 @@ func (k keyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.move, k.focus, k.open},
 		{k.search, k.back, k.detailPane},
-		{k.help, k.quit},
+		{k.sortBy, k.filter},
+		{k.resume, k.watchlist, k.watchView, k.catalogs, k.settings, k.login, k.account, k.library},
+		{k.playback, k.help, k.quit},
 	}
 }
@@ -0,0 +1,197 @@
+package app
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"tuiflix/internal/api"
+	"tuiflix/internal/app/components"
+)
+
+type libraryTab int
+
+const (
+	libraryTorrents libraryTab = iota
+	libraryDownloads
+)
+
+// libraryState is what the library screen shows: the account's torrents
+// and downloads, and the files of the torrent opened with Enter.
+type libraryState struct {
+	tab       libraryTab
+	torrents  []api.Torrent
+	downloads []api.Download
+	torrent   *api.Torrent
+	files     []api.TorrentFile
+}
+
+func (m Model) openLibrary() (tea.Model, tea.Cmd) {
+	if !m.client.RealDebridEnabled() {
+		m.status = "The library needs Real-Debrid, press L to log in"
+		return m, nil
+	}
+	m.mode = modeLibrary
+	m.library = libraryState{}
+	m.syncLibraryList()
+	m.setFocus(focusSettings)
+	m.status = "Loading Real-Debrid library..."
+	return m, loadLibraryCmd(m.requests.start(requestLibrary), m.client)
+}
+
+func (m Model) updateLibraryKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc":
+		switch {
+		case m.resolving != nil:
+			m.cancelResolve()
+			m.status = "Cancelled resolving link"
+		case m.library.torrent != nil:
+			m.requests.cancel(requestLibrary)
+			m.library.torrent = nil
+			m.library.files = nil
+			m.syncLibraryList()
+			m.status = "Back to torrents"
+		default:
+			m.requests.cancel(requestLibrary)
+			m.mode = modeBrowse
+			m.setFocus(focusRight)
+			m.status = "Back to browse"
+		}
+		return m, nil
+	case "tab", "shift+tab":
+		if m.library.torrent != nil {
+			return m, nil
+		}
+		m.library.tab = (m.library.tab + 1) % 2
+		m.syncLibraryList()
+		return m, nil
+	case "r":
+		m.library.torrent = nil
+		m.library.files = nil
+		m.status = "Refreshing Real-Debrid library..."
+		return m, loadLibraryCmd(m.requests.start(requestLibrary), m.client)
+	case "enter":
+		entry, ok := m.libraryList.Selected()
+		if !ok {
+			return m, nil
+		}
+		if m.library.tab == libraryTorrents && m.library.torrent == nil {
+			for idx := range m.library.torrents {
+				if m.library.torrents[idx].ID == entry.ID {
+					torrent := m.library.torrents[idx]
+					m.library.torrent = &torrent
+				}
+			}
+			m.status = "Loading files of " + entry.Name + "..."
+			return m, loadTorrentFilesCmd(m.requests.start(requestLibrary), m.client, entry.ID)
+		}
+		if entry.Link == "" {
+			m.status = "This file is not ready yet"
+			return m, nil
+		}
+		m.status = "Opening " + entry.Name + " in " + m.player.Name() + "..."
+		return m, m.startResolve(libraryPlayRequest(entry, m.library.tab == libraryDownloads))
+	case "y":
+		entry, ok := m.libraryList.Selected()
+		if !ok || entry.Link == "" {
+			return m, nil
+		}
+		m.status = "Copying link..."
+		return m, copyLinkCmd(m.requests.start(requestLibrary), m.client, entry.Link, m.library.tab == libraryTorrents)
+	}
+
+	return m, m.libraryList.Update(msg)
+}
+
+// libraryPlayRequest plays entry without recording it in the watch history.
+// Downloads are already unrestricted, torrent links still need it.
+func libraryPlayRequest(entry components.LibraryEntry, unrestricted bool) playRequest {
+	return playRequest{
+		item:   api.MediaItem{Name: path.Base(entry.Name), Type: "movie"},
+		stream: api.Stream{Name: entry.Name, URL: entry.Link},
+		direct: unrestricted,
+	}
+}
+
+func (m *Model) syncLibraryList() {
+	var entries []components.LibraryEntry
+	switch {
+	case m.library.torrent != nil:
+		m.libraryList.SetTitle("Files: " + compactText(m.library.torrent.Filename, 60))
+		for _, file := range m.library.files {
+			status := "ready"
+			if file.Link == "" {
+				status = "not downloaded yet"
+			}
+			entries = append(entries, components.LibraryEntry{Name: file.Path, Size: file.Bytes, Status: status, Link: file.Link})
+		}
+	case m.library.tab == libraryTorrents:
+		m.libraryList.SetTitle(fmt.Sprintf("Torrents (%d)", len(m.library.torrents)))
+		for _, torrent := range m.library.torrents {
+			status := torrent.Status
+			if torrent.Status == "downloading" {
+				status = fmt.Sprintf("downloading %.0f%%", torrent.Progress)
+			}
+			entries = append(entries, components.LibraryEntry{ID: torrent.ID, Name: torrent.Filename, Size: torrent.Bytes, Status: status, Date: torrent.Added})
+		}
+	default:
+		m.libraryList.SetTitle(fmt.Sprintf("Downloads (%d)", len(m.library.downloads)))
+		for _, download := range m.library.downloads {
+			entries = append(entries, components.LibraryEntry{ID: download.ID, Name: download.Filename, Size: download.Filesize, Status: download.Host, Date: download.Generated, Link: download.Download})
+		}
+	}
+	m.libraryList.SetItems(entries)
+}
+
+func (m *Model) renderLibraryPopup(width int, height int) string {
+	popupW := min(width-6, 110)
+	if popupW < 56 {
+		popupW = width - 2
+	}
+	popupH := min(height-4, 30)
+	if popupH < 14 {
+		popupH = 14
+	}
+
+	tabs := []string{"Torrents", "Downloads"}
+	for idx, tab := range tabs {
+		if libraryTab(idx) == m.library.tab {
+			tabs[idx] = lipgloss.NewStyle().Foreground(accentText).Bold(true).Underline(true).Render(tab)
+		} else {
+			tabs[idx] = lipgloss.NewStyle().Foreground(mutedText).Render(tab)
+		}
+	}
+
+	instructions := "Enter to open, y to copy link, Tab to switch, r to refresh, Esc to close"
+	switch {
+	case m.resolving != nil:
+		instructions = "Resolving with " + m.client.DebridName() + ", Esc to cancel"
+	case m.library.torrent != nil:
+		instructions = "Enter to play, y to copy the direct link, Esc to go back"
+	}
+
+	content := []string{
+		lipgloss.NewStyle().Foreground(accentText).Bold(true).Render("Real-Debrid Library"),
+		strings.Join(tabs, "  "),
+		lipgloss.NewStyle().Foreground(mutedText).Render(compactText(instructions, popupW-4)),
+		m.libraryList.View(popupW-2, popupH-3, true),
+	}
+	if m.resolving != nil {
+		content[1] = lipgloss.NewStyle().Foreground(accentText).Render(renderResolveProgress(m.resolving.progress, popupW-4))
+	}
+
+	style := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(accentText).
+		Padding(0, 1).
+		Width(popupW).
+		Height(popupH)
+
+	return style.Render(strings.Join(content, "\n"))
+}
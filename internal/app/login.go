@@ -0,0 +1,70 @@
+package app
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func (m Model) openLogin() (tea.Model, tea.Cmd) {
+	m.mode = modeLogin
+	m.loginCode = nil
+	m.setFocus(focusSettings)
+	m.status = "Requesting a Real-Debrid device code..."
+	return m, startLoginCmd(m.requests.start(requestLogin))
+}
+
+func (m Model) closeLogin(status string) (tea.Model, tea.Cmd) {
+	m.requests.cancel(requestLogin)
+	m.mode = modeBrowse
+	m.loginCode = nil
+	m.setFocus(focusRight)
+	m.status = status
+	return m, nil
+}
+
+func (m Model) updateLoginKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc":
+		return m.closeLogin("Login cancelled")
+	}
+	return m, nil
+}
+
+func (m *Model) renderLoginPopup(width int, height int) string {
+	popupW := min(width-6, 72)
+	if popupW < 52 {
+		popupW = width - 2
+	}
+	popupH := min(height-4, 10)
+
+	muted := lipgloss.NewStyle().Foreground(mutedText)
+	content := []string{
+		lipgloss.NewStyle().Foreground(accentText).Bold(true).Render("Real-Debrid Login"),
+		muted.Render("Esc to cancel"),
+		"",
+	}
+
+	if code := m.loginCode; code != nil {
+		content = append(content,
+			"1. Open "+lipgloss.NewStyle().Foreground(accentText).Render(code.VerificationURL),
+			"2. Enter the code "+lipgloss.NewStyle().Foreground(warningText).Bold(true).Render(code.UserCode),
+			"",
+			muted.Render("Waiting for approval, the code expires at "+code.ExpiresAt.Format("15:04")),
+		)
+	} else {
+		content = append(content, muted.Render("Requesting a device code..."))
+	}
+
+	style := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(accentText).
+		Padding(0, 1).
+		Width(popupW).
+		Height(popupH)
+
+	return style.Render(strings.Join(content, "\n"))
+}
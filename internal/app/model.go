@@ -1,9 +1,11 @@
 package app
 
 import (
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -11,19 +13,38 @@ import (
 
 	"tuiflix/internal/api"
 	"tuiflix/internal/app/components"
+	"tuiflix/internal/config"
+	"tuiflix/internal/player"
+	"tuiflix/internal/poster"
+	"tuiflix/internal/store"
 )
 
 type viewMode int
 type popupMode int
 type focusArea int
+type browseView int
 
 const (
 	modeBrowse viewMode = iota
 	modeDetail
+	modeSettings
+	modeLogin
+	modeAccount
+	modeLibrary
+	modeCatalog
+)
+
+const (
+	browsePopular browseView = iota
+	browseSearch
+	browseContinue
+	browseWatchlist
+	browseCatalog
 )
 
 const (
 	popupNone popupMode = iota
+	popupInfo
 	popupSeasonEpisode
 	popupStreams
 )
@@ -35,10 +56,18 @@ const (
 	focusStreams
 	focusSeason
 	focusEpisode
+	focusSettings
+	focusCatalogs
+	focusGenres
 )
 
 type Model struct {
 	client *api.Client
+	player player.Player
+	store  *store.Store
+	config config.Config
+
+	posters *poster.Renderer
 
 	width  int
 	height int
@@ -55,23 +84,64 @@ type Model struct {
 	right    components.MediaList
 	streams  components.StreamList
 	seasons  components.NumberList
-	episodes components.NumberList
+	episodes components.EpisodeList
 
 	moviesData         []api.MediaItem
 	showsData          []api.MediaItem
 	searchMovieResults []api.MediaItem
 	searchShowResults  []api.MediaItem
-	showSearch         bool
-
-	selected api.MediaItem
-
-	episodesBySeason map[int][]int
+	searchQuery        string
+	searching          string
+	searchSubmitted    bool
+	popularPages       [2]pager
+	searchPages        [2]pager
+	browse             browseView
+	continueTargets    map[string]continueTarget
+
+	selected      api.MediaItem
+	meta          *api.MediaMeta
+	metaErr       error
+	pendingTarget *continueTarget
+	posterURL     string
+
+	episodesBySeason map[int][]api.Episode
 	streamsReqKey    string
+	allStreams       []api.Stream
+	streamFilter     streamFilter
+	fallback         *playRequest
+	resolving        *resolveState
+	requests         requests
+
+	playback      *player.Controller
+	playbackState player.State
+	nowPlaying    playRequest
+	historySaved  time.Time
+
+	settingsCursor  int
+	settingsEditing bool
+	settingsInput   textinput.Model
+
+	loginCode   *api.DeviceCode
+	account     *accountInfo
+	library     libraryState
+	libraryList components.LibraryList
+
+	catalog     catalogState
+	catalogList components.TextList
+	genreList   components.TextList
+
+	status         string
+	accountWarning string
+}
 
-	status string
+// resolveState tracks the stream currently being resolved so the streams
+// popup can show its progress and Esc can cancel it.
+type resolveState struct {
+	updates  <-chan api.ResolveProgress
+	progress api.ResolveProgress
 }
 
-func NewModel(client *api.Client) Model {
+func NewModel(client *api.Client, p player.Player, st *store.Store, cfg config.Config, posters *poster.Renderer) Model {
 	input := textinput.New()
 	input.Placeholder = "Search movies and TV"
 	input.CharLimit = 140
@@ -79,24 +149,34 @@ func NewModel(client *api.Client) Model {
 	input.Prompt = ""
 	input.Focus()
 
+	settingsInput := textinput.New()
+	settingsInput.CharLimit = 200
+	settingsInput.Width = 40
+	settingsInput.Prompt = ""
+
 	h := help.New()
 	h.ShowAll = false
 
 	status := "Loading popular titles..."
-	if !client.RealDebridEnabled() {
-		status = "REALDEBRID not found: magnet links will open directly in IINA"
+	if !client.DebridEnabled() {
+		status = "No debrid login found (press L for Real-Debrid): magnet links will open directly in " + p.Name()
 	}
 
 	movies := components.NewMediaList("Popular Movies")
 	right := components.NewMediaList("Popular TV Shows")
 	streams := components.NewStreamList("Streams")
 	seasons := components.NewNumberList("Seasons")
-	episodes := components.NewNumberList("Episodes")
+	episodes := components.NewEpisodeList("Episodes")
 	seasons.SetItems([]int{1})
-	episodes.SetItems([]int{1})
+	episodes.SetItems(placeholderEpisodes()[1])
 
 	return Model{
 		client:             client,
+		player:             p,
+		store:              st,
+		config:             cfg,
+		posters:            posters,
+		settingsInput:      settingsInput,
 		mode:               modeBrowse,
 		popup:              popupNone,
 		focus:              focusSearch,
@@ -108,9 +188,12 @@ func NewModel(client *api.Client) Model {
 		streams:            streams,
 		seasons:            seasons,
 		episodes:           episodes,
+		libraryList:        components.NewLibraryList("Torrents"),
+		catalogList:        components.NewTextList("Catalogs"),
+		genreList:          components.NewTextList("Genres"),
 		moviesData:         []api.MediaItem{},
 		showsData:          []api.MediaItem{},
-		episodesBySeason:   map[int][]int{1: []int{1}},
+		episodesBySeason:   placeholderEpisodes(),
 		searchMovieResults: []api.MediaItem{},
 		searchShowResults:  []api.MediaItem{},
 		status:             status,
@@ -118,6 +201,11 @@ func NewModel(client *api.Client) Model {
 }
 
 func (m Model) Init() tea.Cmd {
+	if m.client.RealDebridEnabled() {
+		// Init cannot keep a request's cancel func, so Update starts the
+		// account load.
+		return tea.Batch(loadPopularCmd(m.client), func() tea.Msg { return startAccountMsg{} })
+	}
 	return loadPopularCmd(m.client)
 }
 
@@ -136,44 +224,117 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.moviesData = msg.movies
 		m.showsData = msg.shows
+		m.popularPages = [2]pager{}
 		m.syncBrowsePanes()
 		if m.status == "Loading popular titles..." {
 			m.status = "Browse with arrows/tab, enter opens details"
 		}
-		return m, nil
+		return m, m.requestPoster(m.browsePoster())
 
 	case searchLoadedMsg:
-		if msg.query != strings.TrimSpace(m.input.Value()) {
+		if msg.query != strings.TrimSpace(m.input.Value()) || canceled(msg.err) {
 			return m, nil
 		}
+		m.requests.cancel(requestSearch)
+		m.searching = ""
+		submitted := m.searchSubmitted
+		m.searchSubmitted = false
 		if msg.err != nil {
 			m.status = "Search failed: " + msg.err.Error()
 			return m, nil
 		}
 
 		movieResults, showResults := splitSearchResults(msg.results)
+		m.requests.cancel(requestMoviePage, requestShowPage)
 		m.searchMovieResults = movieResults
 		m.searchShowResults = showResults
-		m.showSearch = true
+		m.searchQuery = msg.query
+		m.searchPages = [2]pager{}
+		m.browse = browseSearch
 		m.syncBrowsePanes()
-
-		if len(movieResults) > 0 {
-			m.setFocus(focusMovies)
-		} else if len(showResults) > 0 {
-			m.setFocus(focusRight)
-		} else {
-			m.setFocus(focusMovies)
+		if submitted {
+			m.focusSearchResults()
 		}
 
 		m.status = fmt.Sprintf("Found %d movie(s), %d series", len(movieResults), len(showResults))
+		return m, m.requestPoster(m.browsePoster())
+
+	case searchDebounceMsg:
+		return m.debouncedSearch(msg.query)
+
+	case catalogsLoadedMsg:
+		if m.mode != modeCatalog || canceled(msg.err) {
+			return m, nil
+		}
+		m.requests.cancel(requestCatalog)
+		if msg.err != nil {
+			m.status = "Failed to load catalogs: " + msg.err.Error()
+			return m, nil
+		}
+		m.catalog.catalogs = msg.catalogs
+		m.syncCatalogLists()
+		m.status = "Pick a catalog and genre, then press Enter"
+		return m, nil
+
+	case catalogLoadedMsg:
+		if !sameCatalog(msg.catalog, m.catalog.current) || msg.genre != m.catalog.genre || canceled(msg.err) {
+			return m, nil
+		}
+		m.requests.cancel(requestCatalog)
+		if msg.err != nil {
+			m.status = "Failed to load " + catalogLabel(msg.catalog, msg.genre, "titles") + ": " + msg.err.Error()
+			return m, nil
+		}
+		m.requests.cancel(requestMoviePage, requestShowPage)
+		m.catalog.movies = msg.movies
+		m.catalog.shows = msg.shows
+		m.catalog.pages = [2]pager{}
+		m.browse = browseCatalog
+		m.syncBrowsePanes()
+		m.status = fmt.Sprintf("%s: %d movie(s), %d series", catalogLabel(msg.catalog, msg.genre, "titles"), len(msg.movies), len(msg.shows))
+		return m, m.requestPoster(m.browsePoster())
+
+	case pageLoadedMsg:
+		m.applyPage(msg)
+		return m, nil
+
+	case metaLoadedMsg:
+		if m.mode != modeDetail || msg.itemID != m.selected.ID || canceled(msg.err) {
+			return m, nil
+		}
+		m.requests.cancel(requestMeta)
+		if msg.err != nil {
+			m.metaErr = msg.err
+			if m.popup == popupInfo {
+				m.status = "Failed to load details: " + msg.err.Error()
+			}
+			return m, nil
+		}
+		m.meta = &msg.meta
+		if m.popup == popupInfo {
+			m.status = m.infoStatus()
+		}
+		return m, m.requestPoster(m.detailPoster())
+
+	case posterLoadedMsg:
+		if msg.url == m.posterURL {
+			m.posterURL = ""
+			m.requests.cancel(requestPoster)
+		}
 		return m, nil
 
 	case episodesLoadedMsg:
-		if m.mode != modeDetail || msg.itemID != m.selected.ID {
+		if m.mode != modeDetail || msg.itemID != m.selected.ID || canceled(msg.err) {
 			return m, nil
 		}
+		m.requests.cancel(requestEpisodes)
 		if msg.err != nil {
 			m.status = "Failed to load season/episode metadata: " + msg.err.Error()
+			if m.pendingTarget != nil {
+				m.pendingTarget = nil
+				m.popup = popupSeasonEpisode
+				m.setFocus(focusSeason)
+			}
 			return m, nil
 		}
 
@@ -187,35 +348,283 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.seasons.SetItems(seasonOptions)
 		m.seasons.SetCursor(indexOfInt(seasonOptions, prevSeason))
 		m.syncEpisodeOptions(true)
-		m.status = "Pick season/episode, then press Enter"
+
+		if target := m.pendingTarget; target != nil {
+			m.pendingTarget = nil
+			return m, m.jumpToEpisode(*target)
+		}
+
+		if m.popup == popupSeasonEpisode {
+			m.status = "Pick season/episode, then press Enter"
+		}
 		return m, nil
 
 	case streamsLoadedMsg:
-		if m.mode != modeDetail || msg.key != m.streamsReqKey {
+		if m.mode != modeDetail || msg.key != m.streamsReqKey || canceled(msg.err) {
 			return m, nil
 		}
-		if msg.err != nil {
-			m.streams.SetItems(nil)
+		m.requests.cancel(requestStreams)
+		var sourceErrs api.SourceErrors
+		partial := errors.As(msg.err, &sourceErrs) && len(msg.streams) > 0
+		if msg.err != nil && !partial {
+			m.setStreams(nil)
 			m.status = "Failed to load streams: " + msg.err.Error()
 			return m, nil
 		}
-		m.streams.SetItems(msg.streams)
+		shown := m.setStreams(msg.streams)
 		if len(msg.streams) == 0 {
 			m.status = "No streams found for this selection"
 		} else {
-			m.status = fmt.Sprintf("Loaded %d stream(s). Enter opens in IINA", len(msg.streams))
+			m.status = fmt.Sprintf("Loaded %d stream(s). Enter opens in %s", len(msg.streams), m.player.Name())
+			if hidden := len(msg.streams) - shown; hidden > 0 {
+				m.status += fmt.Sprintf(", %d hidden by filters", hidden)
+			}
+		}
+		if partial {
+			m.status += " (" + sourceErrs.Summary() + ")"
+		}
+		if len(msg.streams) > 0 && m.client.DebridEnabled() {
+			return m, checkCacheCmd(m.requests.start(requestCache), m.client, msg.streams, msg.key)
 		}
 		return m, nil
 
+	case cacheCheckedMsg:
+		if m.mode != modeDetail || msg.key != m.streamsReqKey || canceled(msg.err) {
+			return m, nil
+		}
+		m.requests.cancel(requestCache)
+		if msg.err != nil {
+			m.status = "Cache check failed: " + msg.err.Error()
+		}
+
+		selected, hadSelection := m.streams.Selected()
+		m.setStreams(msg.streams)
+		if hadSelection {
+			m.streams.SelectMatching(selected)
+		}
+
+		cached := 0
+		for _, stream := range msg.streams {
+			if stream.Cached {
+				cached++
+			}
+		}
+		if msg.err == nil && cached > 0 {
+			m.status = fmt.Sprintf("%d of %d stream(s) cached on %s", cached, len(msg.streams), m.client.DebridName())
+		}
+		return m, nil
+
+	case resolveProgressMsg:
+		if m.resolving == nil || msg.updates != m.resolving.updates {
+			return m, nil
+		}
+		if msg.closed {
+			m.cancelResolve()
+			return m, nil
+		}
+		m.resolving.progress = msg.progress
+		return m, waitResolveProgressCmd(msg.updates)
+
 	case streamOpenedMsg:
+		if canceled(msg.err) {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.status = m.describeOpenError(msg.err)
+			if m.config.AllowFallback && !msg.req.direct && m.mode == modeDetail && isDebridError(msg.err) {
+				req := msg.req
+				req.direct = true
+				m.fallback = &req
+			}
+			return m, nil
+		}
+		if msg.req.item.ID != "" {
+			m.store.History.Record(msg.req.item, msg.req.season, msg.req.episode, msg.req.stream)
+		}
+		m.status = "Opening stream in " + m.player.Name()
+		if msg.req.start > 0 {
+			m.status = fmt.Sprintf("Resuming at %s in %s", formatClock(msg.req.start), m.player.Name())
+		}
+		if msg.controller == nil {
+			return m, saveHistoryCmd(m.store.History)
+		}
+
+		var stopPrevious tea.Cmd
+		if m.playback != nil {
+			stopPrevious = playbackCommandCmd(m.playback.Stop)
+		}
+		m.playback = msg.controller
+		m.playbackState = player.State{}
+		m.nowPlaying = msg.req
+		m.historySaved = time.Now()
+		return m, tea.Batch(stopPrevious, waitPlaybackCmd(msg.controller), saveHistoryCmd(m.store.History))
+
+	case playbackMsg:
+		if msg.controller != m.playback {
+			if msg.closed {
+				return m, nil
+			}
+			return m, waitPlaybackCmd(msg.controller)
+		}
+		if msg.closed {
+			m.playback = nil
+			return m, nil
+		}
+
+		m.playbackState = msg.state
+		req := m.nowPlaying
+		m.store.History.UpdatePosition(req.item.ID, req.season, req.episode, msg.state.Position, msg.state.Duration, msg.state.ExitReason == "eof")
+
+		cmds := []tea.Cmd{waitPlaybackCmd(msg.controller)}
+		if msg.state.Exited || msg.state.Paused || time.Since(m.historySaved) > 15*time.Second {
+			m.historySaved = time.Now()
+			cmds = append(cmds, saveHistoryCmd(m.store.History))
+		}
+		if msg.state.Exited {
+			m.playback = nil
+			m.status = "Playback ended: " + msg.state.ExitReason
+			if m.browse == browseContinue {
+				m.syncBrowsePanes()
+			}
+		}
+		return m, tea.Batch(cmds...)
+
+	case historySavedMsg:
+		if msg.err != nil {
+			m.status = "Failed to save watch history: " + msg.err.Error()
+		}
+		return m, nil
+
+	case configSavedMsg:
+		if msg.err != nil {
+			m.status = "Failed to save settings: " + msg.err.Error()
+		}
+		return m, nil
+
+	case watchlistSavedMsg:
+		if msg.err != nil {
+			m.status = "Failed to save watchlist: " + msg.err.Error()
+		}
+		return m, nil
+
+	case loginStartedMsg:
+		if m.mode != modeLogin || canceled(msg.err) {
+			return m, nil
+		}
+		if msg.err != nil {
+			return m.closeLogin("Real-Debrid login failed: " + msg.err.Error())
+		}
+		m.loginCode = &msg.code
+		m.status = "Approve tuiflix on the Real-Debrid website"
+		return m, waitLoginCmd(m.requests.start(requestLogin), m.config, msg.code)
+
+	case loginDoneMsg:
+		if m.mode != modeLogin || canceled(msg.err) {
+			return m, nil
+		}
+		if msg.err != nil {
+			return m.closeLogin("Real-Debrid login failed: " + msg.err.Error())
+		}
+		m.client = msg.client
+		if !m.client.RealDebridEnabled() {
+			return m.closeLogin("Saved the Real-Debrid login, but " + m.client.DebridName() + " is the configured provider")
+		}
+		model, cmd := m.closeLogin("Logged in to Real-Debrid")
+		return model, tea.Batch(cmd, loadAccountCmd(m.requests.start(requestAccount), m.client))
+
+	case startAccountMsg:
+		return m, loadAccountCmd(m.requests.start(requestAccount), m.client)
+
+	case accountLoadedMsg:
+		if canceled(msg.err) {
+			return m, nil
+		}
+		m.requests.cancel(requestAccount)
+		if msg.err != nil {
+			if m.mode == modeAccount {
+				m.status = "Failed to load Real-Debrid account: " + msg.err.Error()
+			}
+			if errors.Is(msg.err, api.ErrDebridAuth) {
+				m.accountWarning = "Real-Debrid rejected the login, press L to log in again"
+			}
+			if msg.info.account.Username == "" {
+				return m, nil
+			}
+		}
+		m.account = &msg.info
+		m.accountWarning = premiumWarning(msg.info.account)
+		if m.mode == modeAccount && msg.err == nil {
+			m.status = "Real-Debrid account loaded"
+		}
+		return m, nil
+
+	case libraryLoadedMsg:
+		if m.mode != modeLibrary || canceled(msg.err) {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.status = "Failed to load Real-Debrid library: " + msg.err.Error()
+			if len(msg.torrents) == 0 {
+				return m, nil
+			}
+		}
+		m.library.torrents = msg.torrents
+		m.library.downloads = msg.downloads
+		m.syncLibraryList()
+		if msg.err == nil {
+			m.status = fmt.Sprintf("%d torrent(s), %d download(s)", len(msg.torrents), len(msg.downloads))
+		}
+		return m, nil
+
+	case torrentFilesLoadedMsg:
+		if m.mode != modeLibrary || m.library.torrent == nil || m.library.torrent.ID != msg.torrentID || canceled(msg.err) {
+			return m, nil
+		}
 		if msg.err != nil {
-			m.status = "Unable to open stream: " + msg.err.Error()
+			m.library.torrent = nil
+			m.status = "Failed to load torrent files: " + msg.err.Error()
 			return m, nil
 		}
-		m.status = "Opening stream in IINA"
+		m.library.files = msg.files
+		m.syncLibraryList()
+		m.status = fmt.Sprintf("%d file(s), Enter plays in %s", len(msg.files), m.player.Name())
+		return m, nil
+
+	case linkCopiedMsg:
+		if canceled(msg.err) {
+			return m, nil
+		}
+		switch {
+		case msg.err != nil && msg.link != "":
+			m.status = "Clipboard unavailable (" + msg.err.Error() + "), link: " + msg.link
+		case msg.err != nil:
+			m.status = "Failed to unrestrict link: " + msg.err.Error()
+		default:
+			m.status = "Copied direct link to the clipboard"
+		}
+		return m, nil
+
+	case playbackErrMsg:
+		m.status = "Player command failed: " + msg.err.Error()
 		return m, nil
 
 	case tea.KeyMsg:
+		if m.mode == modeSettings {
+			return m.updateSettingsKey(msg)
+		}
+		if m.mode == modeLogin {
+			return m.updateLoginKey(msg)
+		}
+		if m.mode == modeAccount {
+			return m.updateAccountKey(msg)
+		}
+		if m.mode == modeLibrary {
+			return m.updateLibraryKey(msg)
+		}
+		if m.mode == modeCatalog {
+			return m.updateCatalogKey(msg)
+		}
+
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
@@ -229,21 +638,44 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		if m.playback != nil && m.focus != focusSearch {
+			if cmd, ok := m.updatePlaybackKey(msg); ok {
+				return m, cmd
+			}
+		}
+
 		if m.mode == modeBrowse {
-			return m.updateBrowseKey(msg)
+			model, cmd := m.updateBrowseKey(msg)
+			m = model.(Model)
+			if m.mode == modeBrowse {
+				cmd = tea.Batch(cmd, m.requestPoster(m.browsePoster()), m.loadNextPage())
+			}
+			return m, cmd
 		}
 		return m.updateDetailKey(msg)
 	}
 
 	if m.mode == modeBrowse && m.focus == focusSearch {
-		var cmd tea.Cmd
-		m.input, cmd = m.input.Update(msg)
-		return m, cmd
+		return m.updateSearchInput(msg)
 	}
 
 	return m, nil
 }
 
+func (m Model) updatePlaybackKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch msg.String() {
+	case "p":
+		return playbackCommandCmd(m.playback.TogglePause), true
+	case "[":
+		return playbackCommandCmd(func() error { return m.playback.Seek(-10) }), true
+	case "]":
+		return playbackCommandCmd(func() error { return m.playback.Seek(10) }), true
+	case "x":
+		return playbackCommandCmd(m.playback.Stop), true
+	}
+	return nil, false
+}
+
 func (m Model) updateBrowseKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "tab":
@@ -259,46 +691,99 @@ func (m Model) updateBrowseKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.setFocus(focusRight)
 		return m, nil
 	case "esc":
-		if m.showSearch {
-			m.showSearch = false
-			m.searchMovieResults = nil
-			m.searchShowResults = nil
+		if m.searching != "" {
+			m.cancelSearch()
+			m.status = "Search cancelled"
+			return m, nil
+		}
+		switch m.browse {
+		case browseSearch:
+			m.clearSearch()
+		case browseContinue, browseWatchlist, browseCatalog:
+			m.requests.cancel(requestCatalog)
+			m.browse = browsePopular
 			m.syncBrowsePanes()
-			m.status = "Search cleared"
+			m.status = "Back to popular titles"
 		}
 		return m, nil
+	case "c":
+		if m.focus == focusSearch {
+			break
+		}
+		m.toggleBrowseView(browseContinue, "Continue watching: Enter resumes where you left off")
+		return m, nil
+	case "S":
+		if m.focus == focusSearch {
+			break
+		}
+		return m.openSettings()
+	case "L":
+		if m.focus == focusSearch {
+			break
+		}
+		return m.openLogin()
+	case "A":
+		if m.focus == focusSearch {
+			break
+		}
+		return m.openAccount()
+	case "R":
+		if m.focus == focusSearch {
+			break
+		}
+		return m.openLibrary()
+	case "W":
+		if m.focus == focusSearch {
+			break
+		}
+		m.toggleBrowseView(browseWatchlist, "Watchlist: w removes the selected title")
+		return m, nil
+	case "C":
+		if m.focus == focusSearch {
+			break
+		}
+		return m.openCatalogs()
+	case "w":
+		if m.focus == focusSearch {
+			break
+		}
+		item, ok := m.currentBrowseSelection()
+		if !ok {
+			return m, nil
+		}
+		if m.store.Watchlist.Toggle(item) {
+			m.status = "Added to watchlist: " + item.Name
+		} else {
+			m.status = "Removed from watchlist: " + item.Name
+		}
+		m.syncBrowsePanes()
+		return m, saveWatchlistCmd(m.store.Watchlist)
 	case "enter":
 		if m.focus == focusSearch {
-			query := strings.TrimSpace(m.input.Value())
-			if query == "" {
-				m.showSearch = false
-				m.searchMovieResults = nil
-				m.searchShowResults = nil
-				m.syncBrowsePanes()
-				m.status = "Search cleared"
-				return m, nil
-			}
-			m.status = "Searching..."
-			return m, loadSearchCmd(m.client, query)
+			return m.submitSearch()
 		}
 
 		item, ok := m.currentBrowseSelection()
 		if !ok {
 			return m, nil
 		}
+		if target, ok := m.continueTargets[item.ID]; ok && m.browse == browseContinue && item.Type == "series" {
+			return m.openEpisode(item, target)
+		}
 		return m.openDetail(item)
 	}
 
 	if m.focus == focusSearch {
-		var cmd tea.Cmd
-		m.input, cmd = m.input.Update(msg)
-		return m, cmd
+		return m.updateSearchInput(msg)
 	}
 
 	return m, m.updateBrowseList(msg)
 }
 
 func (m Model) updateDetailKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.popup == popupInfo {
+		return m.updateInfoPopupKey(msg)
+	}
 	if m.popup == popupSeasonEpisode {
 		return m.updateSeasonEpisodePopupKey(msg)
 	}
@@ -314,10 +799,10 @@ func (m Model) updateSeasonEpisodePopupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 		m.setFocus(focusSeason)
 		return m, nil
 	case "esc":
-		return m.closeDetail()
+		return m.showInfo()
 	case "enter":
 		m.popup = popupStreams
-		m.streams.SetItems(nil)
+		m.setStreams(nil)
 		m.setFocus(focusStreams)
 		return m, m.reloadStreamsCmd()
 	}
@@ -326,27 +811,120 @@ func (m Model) updateSeasonEpisodePopupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 }
 
 func (m Model) updateStreamsPopupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.fallback != nil {
+		req := *m.fallback
+		switch msg.String() {
+		case "y":
+			m.fallback = nil
+			m.status = "Opening raw link in " + m.player.Name() + "..."
+			return m, m.startResolve(req)
+		case "n", "esc":
+			m.fallback = nil
+			m.status = "Fallback cancelled"
+		}
+		return m, nil
+	}
+
+	if m.resolving != nil && msg.String() == "esc" {
+		m.cancelResolve()
+		m.status = "Cancelled resolving stream"
+		return m, nil
+	}
+
 	switch msg.String() {
 	case "esc":
 		if m.selected.Type == "series" {
+			m.requests.cancel(requestStreams, requestCache)
 			m.popup = popupSeasonEpisode
 			m.setFocus(focusSeason)
 			m.status = "Pick season/episode, then press Enter"
 			return m, nil
 		}
-		return m.closeDetail()
+		m.requests.cancel(requestStreams, requestCache)
+		return m.showInfo()
 	case "enter":
 		stream, ok := m.streams.Selected()
 		if !ok {
 			return m, nil
 		}
 		m.status = "Resolving stream URL..."
-		return m, openStreamCmd(m.client, stream)
+		return m, m.startResolve(m.newPlayRequest(stream))
+	case "s":
+		m.streamFilter.sort = (m.streamFilter.sort + 1) % streamSort(len(streamSortNames))
+		m.refilterStreams()
+		return m, nil
+	case "4":
+		m.streamFilter.hide4K = !m.streamFilter.hide4K
+		m.refilterStreams()
+		return m, nil
+	case "c":
+		m.streamFilter.hideCam = !m.streamFilter.hideCam
+		m.refilterStreams()
+		return m, nil
+	case "m":
+		m.streamFilter.maxSize = m.streamFilter.nextMaxSize()
+		m.refilterStreams()
+		return m, nil
+	case "d":
+		m.streamFilter.cachedOnly = !m.streamFilter.cachedOnly
+		m.refilterStreams()
+		return m, nil
 	}
 
 	return m, m.updateDetailList(msg)
 }
 
+// startResolve opens req, cancelling any resolve that is still running.
+func (m *Model) startResolve(req playRequest) tea.Cmd {
+	ctx := m.requests.start(requestResolve)
+	updates := make(chan api.ResolveProgress, 4)
+	m.resolving = &resolveState{updates: updates}
+	return tea.Batch(
+		openStreamCmd(ctx, m.client, m.player, req, updates),
+		waitResolveProgressCmd(updates),
+	)
+}
+
+func (m *Model) cancelResolve() {
+	m.requests.cancel(requestResolve)
+	m.resolving = nil
+}
+
+func (m Model) describeOpenError(err error) string {
+	provider := m.client.DebridName()
+	switch {
+	case errors.Is(err, api.ErrNotCached):
+		return fmt.Sprintf("Not cached on %s yet: %v", provider, err)
+	case errors.Is(err, api.ErrDebridAuth):
+		return fmt.Sprintf("%s rejected the API key, check your token and premium status: %v", provider, err)
+	case errors.Is(err, api.ErrDebridQuota):
+		return fmt.Sprintf("%s limit reached, try again later: %v", provider, err)
+	case errors.Is(err, api.ErrTimeout):
+		return fmt.Sprintf("Timed out waiting for %s: %v", provider, err)
+	default:
+		return "Unable to open stream: " + err.Error()
+	}
+}
+
+func isDebridError(err error) bool {
+	return errors.Is(err, api.ErrDebrid)
+}
+
+// setStreams replaces the unfiltered stream results and returns how many
+// remain visible after the active filters.
+func (m *Model) setStreams(streams []api.Stream) int {
+	m.allStreams = streams
+	visible := m.streamFilter.apply(streams)
+	m.streams.SetItems(visible)
+	return len(visible)
+}
+
+func (m *Model) refilterStreams() {
+	shown := m.setStreams(m.allStreams)
+	m.streams.SetCursor(0)
+	m.status = fmt.Sprintf("Showing %d of %d stream(s)", shown, len(m.allStreams))
+}
+
 func (m *Model) updateBrowseList(msg tea.Msg) tea.Cmd {
 	switch m.focus {
 	case focusMovies:
@@ -377,30 +955,67 @@ func (m *Model) updateDetailList(msg tea.Msg) tea.Cmd {
 }
 
 func (m Model) openDetail(item api.MediaItem) (tea.Model, tea.Cmd) {
+	m.cancelDetailRequests()
 	m.mode = modeDetail
 	m.selected = item
+	m.pendingTarget = nil
+	m.meta = nil
+	m.metaErr = nil
 	m.streams.SetTitle("Streams: " + compactText(item.Name, 40))
-	m.streams.SetItems(nil)
-	m.episodesBySeason = map[int][]int{1: []int{1}}
+	m.setStreams(nil)
+	m.episodesBySeason = placeholderEpisodes()
 	m.seasons.SetItems([]int{1})
 	m.seasons.SetCursor(0)
-	m.episodes.SetItems([]int{1})
+	m.episodes.SetItems(m.episodesBySeason[1])
 	m.episodes.SetCursor(0)
 
+	m.popup = popupInfo
+	m.setFocus(focusStreams)
+	m.status = "Loading details..."
+	cmds := []tea.Cmd{loadMetaCmd(m.requests.start(requestMeta), m.client, item), m.requestPoster(item.Poster)}
 	if item.Type == "series" {
-		m.popup = popupSeasonEpisode
-		m.setFocus(focusSeason)
-		m.status = "Loading seasons and episodes..."
-		return m, loadEpisodesCmd(m.client, item.ID)
+		// Episodes load behind the details so the picker is ready at once.
+		cmds = append(cmds, loadEpisodesCmd(m.requests.start(requestEpisodes), m.client, item.ID))
 	}
+	return m, tea.Batch(cmds...)
+}
+
+// openEpisode opens a series straight into the streams popup for target,
+// skipping the season/episode picker once metadata has loaded.
+func (m Model) openEpisode(item api.MediaItem, target continueTarget) (tea.Model, tea.Cmd) {
+	model, cmd := m.openDetail(item)
+	m = model.(Model)
+	m.pendingTarget = &target
+	m.popup = popupStreams
+	m.setFocus(focusStreams)
+	m.status = "Finding your episode..."
+	return m, cmd
+}
+
+func (m *Model) jumpToEpisode(target continueTarget) tea.Cmd {
+	season, episode := target.season, target.episode
+	if target.advance {
+		next, nextEp, ok := nextEpisode(m.episodesBySeason, season, episode, time.Now())
+		if !ok {
+			m.popup = popupSeasonEpisode
+			m.setFocus(focusSeason)
+			m.status = fmt.Sprintf("No episode after S%02dE%02d yet", season, episode)
+			return nil
+		}
+		season, episode = next, nextEp
+	}
+
+	m.seasons.SetCursor(indexOfInt(sortedMapKeys(m.episodesBySeason), season))
+	m.syncEpisodeOptions(true)
+	m.episodes.SetCursor(indexOfEpisode(m.episodesBySeason[m.currentSeason()], episode))
 
 	m.popup = popupStreams
 	m.setFocus(focusStreams)
-	m.status = "Loading streams..."
-	return m, m.reloadStreamsCmd()
+	return m.reloadStreamsCmd()
 }
 
 func (m Model) closeDetail() (tea.Model, tea.Cmd) {
+	m.cancelDetailRequests()
 	m.mode = modeBrowse
 	m.popup = popupNone
 	m.setFocus(focusRight)
@@ -408,13 +1023,63 @@ func (m Model) closeDetail() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// cancelDetailRequests aborts everything started for the selected title.
+// A resolve that is still running is abandoned too.
+func (m *Model) cancelDetailRequests() {
+	m.requests.cancel(requestMeta, requestEpisodes, requestStreams, requestCache)
+	m.cancelResolve()
+	m.fallback = nil
+}
+
+func (m *Model) toggleBrowseView(view browseView, status string) {
+	if m.browse == view {
+		m.browse = browsePopular
+		m.status = "Back to popular titles"
+	} else {
+		m.browse = view
+		m.status = status
+	}
+	m.syncBrowsePanes()
+}
+
 func (m *Model) syncBrowsePanes() {
-	if m.showSearch {
+	watchlisted := m.store.Watchlist.IDs()
+	m.movies.SetMarked(watchlisted)
+	m.right.SetMarked(watchlisted)
+	m.movies.SetNotes(nil)
+	m.right.SetNotes(nil)
+	m.syncPageLoading()
+
+	switch m.browse {
+	case browseSearch:
 		m.movies.SetTitle(fmt.Sprintf("Movie Results (%d)", len(m.searchMovieResults)))
 		m.movies.SetItems(m.searchMovieResults)
 		m.right.SetTitle(fmt.Sprintf("Series Results (%d)", len(m.searchShowResults)))
 		m.right.SetItems(m.searchShowResults)
 		return
+	case browseContinue:
+		movies, shows, targets, notes := buildContinueWatching(m.store.History.Entries())
+		m.continueTargets = targets
+		m.movies.SetNotes(notes)
+		m.right.SetNotes(notes)
+		m.movies.SetTitle(fmt.Sprintf("Continue Movies (%d)", len(movies)))
+		m.movies.SetItems(movies)
+		m.right.SetTitle(fmt.Sprintf("Up Next (%d)", len(shows)))
+		m.right.SetItems(shows)
+		return
+	case browseWatchlist:
+		movies, shows := splitSearchResults(m.store.Watchlist.Items())
+		m.movies.SetTitle(fmt.Sprintf("Watchlist Movies (%d)", len(movies)))
+		m.movies.SetItems(movies)
+		m.right.SetTitle(fmt.Sprintf("Watchlist Series (%d)", len(shows)))
+		m.right.SetItems(shows)
+		return
+	case browseCatalog:
+		m.movies.SetTitle(catalogLabel(m.catalog.current, m.catalog.genre, "Movies"))
+		m.movies.SetItems(m.catalog.movies)
+		m.right.SetTitle(catalogLabel(m.catalog.current, m.catalog.genre, "TV Shows"))
+		m.right.SetItems(m.catalog.shows)
+		return
 	}
 
 	m.movies.SetTitle("Popular Movies")
@@ -440,9 +1105,9 @@ func splitSearchResults(items []api.MediaItem) ([]api.MediaItem, []api.MediaItem
 
 func (m *Model) syncEpisodeOptions(resetCursor bool) {
 	season := m.currentSeason()
-	episodes := append([]int(nil), m.episodesBySeason[season]...)
+	episodes := m.episodesBySeason[season]
 	if len(episodes) == 0 {
-		episodes = []int{1}
+		episodes = []api.Episode{{Season: season, Number: 1}}
 	}
 
 	previous := m.currentEpisode()
@@ -451,7 +1116,7 @@ func (m *Model) syncEpisodeOptions(resetCursor bool) {
 		m.episodes.SetCursor(0)
 		return
 	}
-	m.episodes.SetCursor(indexOfInt(episodes, previous))
+	m.episodes.SetCursor(indexOfEpisode(episodes, previous))
 }
 
 func (m *Model) reloadStreamsCmd() tea.Cmd {
@@ -460,7 +1125,8 @@ func (m *Model) reloadStreamsCmd() tea.Cmd {
 	key := fmt.Sprintf("%s:%d:%d", m.selected.ID, season, episode)
 	m.streamsReqKey = key
 	m.status = fmt.Sprintf("Loading streams for S%02dE%02d...", season, episode)
-	return loadStreamsCmd(m.client, m.selected, season, episode, key)
+	m.requests.cancel(requestCache)
+	return loadStreamsCmd(m.requests.start(requestStreams), m.client, m.selected, season, episode, key)
 }
 
 func (m *Model) cycleBrowseFocus(reverse bool) {
@@ -499,6 +1165,18 @@ func (m Model) currentBrowseSelection() (api.MediaItem, bool) {
 	return m.right.Selected()
 }
 
+func (m Model) newPlayRequest(stream api.Stream) playRequest {
+	req := playRequest{item: m.selected, stream: stream}
+	if m.selected.Type == "series" {
+		req.season = m.currentSeason()
+		req.episode = m.currentEpisode()
+	}
+	if entry, ok := m.store.History.Lookup(req.item.ID, req.season, req.episode); ok {
+		req.start = entry.ResumeAt()
+	}
+	return req
+}
+
 func (m Model) currentSeason() int {
 	season, ok := m.seasons.Selected()
 	if !ok {
@@ -512,7 +1190,7 @@ func (m Model) currentEpisode() int {
 	if !ok {
 		return 1
 	}
-	return episode
+	return episode.Number
 }
 
 func cycleInOrder(order []focusArea, current focusArea, reverse bool) focusArea {
@@ -540,7 +1218,12 @@ func cycleInOrder(order []focusArea, current focusArea, reverse bool) focusArea
 	return order[idx]
 }
 
-func sortedMapKeys(values map[int][]int) []int {
+// placeholderEpisodes stands in for a series' episodes until they load.
+func placeholderEpisodes() map[int][]api.Episode {
+	return map[int][]api.Episode{1: {{Season: 1, Number: 1}}}
+}
+
+func sortedMapKeys(values map[int][]api.Episode) []int {
 	keys := make([]int, 0, len(values))
 	for k := range values {
 		keys = append(keys, k)
@@ -558,6 +1241,15 @@ func indexOfInt(values []int, target int) int {
 	return 0
 }
 
+func indexOfEpisode(episodes []api.Episode, number int) int {
+	for idx, episode := range episodes {
+		if episode.Number == number {
+			return idx
+		}
+	}
+	return 0
+}
+
 func compactText(input string, width int) string {
 	if width <= 0 {
 		return ""
@@ -0,0 +1,163 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tuiflix/internal/api"
+	"tuiflix/internal/app/components"
+)
+
+// pageAhead is how close the cursor gets to the end of a pane before the
+// next page loads.
+const pageAhead = 5
+
+type pane int
+
+const (
+	paneMovies pane = iota
+	paneShows
+)
+
+func (p pane) mediaType() string {
+	if p == paneShows {
+		return "series"
+	}
+	return "movie"
+}
+
+func (p pane) request() requestKind {
+	if p == paneShows {
+		return requestShowPage
+	}
+	return requestMoviePage
+}
+
+// pager tracks the further pages of one pane's listing.
+type pager struct {
+	loading bool
+	done    bool
+}
+
+// pageQuery is a request for the page of a listing after its first skip
+// titles. source tells listings of one view apart, such as two searches.
+type pageQuery struct {
+	view    browseView
+	pane    pane
+	source  string
+	skip    int
+	search  string
+	catalog api.BrowseCatalog
+	genre   string
+}
+
+// loadNextPage fetches the next page of the focused pane once the cursor
+// nears its end. Empty panes wait for their first page instead.
+func (m *Model) loadNextPage() tea.Cmd {
+	var (
+		p    pane
+		list components.MediaList
+	)
+	switch m.focus {
+	case focusMovies:
+		p, list = paneMovies, m.movies
+	case focusRight:
+		p, list = paneShows, m.right
+	default:
+		return nil
+	}
+
+	items, pages := m.pageData(m.browse, p)
+	if pages == nil || pages.loading || pages.done || len(*items) == 0 || list.Cursor() < len(*items)-pageAhead {
+		return nil
+	}
+
+	pages.loading = true
+	m.syncBrowsePanes()
+	query := pageQuery{
+		view:    m.browse,
+		pane:    p,
+		source:  m.pageSource(m.browse),
+		skip:    len(*items),
+		search:  m.searchQuery,
+		catalog: m.catalog.current,
+		genre:   m.catalog.genre,
+	}
+	return loadPageCmd(m.requests.start(p.request()), m.client, query)
+}
+
+// applyPage appends a loaded page to the listing it belongs to. Titles the
+// listing already has are dropped, and a page without new ones ends it.
+func (m *Model) applyPage(msg pageLoadedMsg) {
+	items, pages := m.pageData(msg.query.view, msg.query.pane)
+	if pages == nil || msg.query.source != m.pageSource(msg.query.view) || msg.query.skip != len(*items) {
+		return
+	}
+	pages.loading = false
+
+	switch {
+	case canceled(msg.err):
+	case msg.err != nil:
+		pages.done = true
+		m.status = "Failed to load more titles: " + msg.err.Error()
+	default:
+		m.requests.cancel(msg.query.pane.request())
+		seen := make(map[string]bool, len(*items))
+		for _, item := range *items {
+			seen[item.ID] = true
+		}
+		added := 0
+		for _, item := range msg.items {
+			if !seen[item.ID] {
+				seen[item.ID] = true
+				*items = append(*items, item)
+				added++
+			}
+		}
+		pages.done = added == 0
+	}
+
+	if m.browse == msg.query.view {
+		m.syncBrowsePanes()
+	}
+}
+
+// pageData returns the titles and pager of a pane. Views that do not page,
+// like the watchlist, return nil.
+func (m *Model) pageData(view browseView, p pane) (*[]api.MediaItem, *pager) {
+	switch view {
+	case browsePopular:
+		if p == paneShows {
+			return &m.showsData, &m.popularPages[p]
+		}
+		return &m.moviesData, &m.popularPages[p]
+	case browseSearch:
+		if p == paneShows {
+			return &m.searchShowResults, &m.searchPages[p]
+		}
+		return &m.searchMovieResults, &m.searchPages[p]
+	case browseCatalog:
+		if p == paneShows {
+			return &m.catalog.shows, &m.catalog.pages[p]
+		}
+		return &m.catalog.movies, &m.catalog.pages[p]
+	}
+	return nil, nil
+}
+
+func (m Model) pageSource(view browseView) string {
+	switch view {
+	case browseSearch:
+		return m.searchQuery
+	case browseCatalog:
+		return m.catalog.current.Addon + "/" + m.catalog.current.ID + "/" + m.catalog.genre
+	}
+	return ""
+}
+
+// syncPageLoading shows the loading rows of the view's panes.
+func (m *Model) syncPageLoading() {
+	_, movies := m.pageData(m.browse, paneMovies)
+	_, shows := m.pageData(m.browse, paneShows)
+	m.movies.SetLoading(movies != nil && movies.loading)
+	m.right.SetLoading(shows != nil && shows.loading)
+}
@@ -0,0 +1,97 @@
+package app
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// posterPaneMinWidth is the terminal width below which the browse view
+// leaves out the poster pane.
+const posterPaneMinWidth = 110
+
+// requestPoster fetches the poster at url unless it is cached, known to be
+// broken or already on its way. A newer poster replaces the one in flight.
+func (m *Model) requestPoster(url string) tea.Cmd {
+	if !m.posters.Enabled() || url == m.posterURL || !m.posters.Cache().Wanted(url) {
+		return nil
+	}
+	m.posterURL = url
+	return loadPosterCmd(m.requests.start(requestPoster), m.posters.Cache(), url)
+}
+
+// listPosters reports whether the browse view shows the selected title's
+// poster beside the lists.
+func (m Model) listPosters() bool {
+	return m.config.Posters.InList && m.posters.Enabled() && m.width >= posterPaneMinWidth
+}
+
+func (m Model) browsePoster() string {
+	if !m.listPosters() {
+		return ""
+	}
+	item, ok := m.currentBrowseSelection()
+	if !ok {
+		return ""
+	}
+	return item.Poster
+}
+
+func (m Model) detailPoster() string {
+	if m.meta != nil && m.meta.Poster != "" {
+		return m.meta.Poster
+	}
+	return m.selected.Poster
+}
+
+// posterBox draws the poster at url into cols x rows cells. The box stays
+// blank while the poster loads; ok is false when there is nothing to show.
+func (m Model) posterBox(url string, cols int, rows int) ([]string, bool) {
+	if !m.posters.Enabled() || url == "" || cols <= 0 || rows <= 0 {
+		return nil, false
+	}
+	if lines, ok := m.posters.Render(url, cols, rows); ok {
+		return lines, true
+	}
+	if !m.posters.Cache().Wanted(url) {
+		return nil, false
+	}
+	lines := make([]string, rows)
+	for idx := range lines {
+		lines[idx] = strings.Repeat(" ", cols)
+	}
+	return lines, true
+}
+
+// renderPosterPane shows the poster of the selected browse item. Images are
+// only drawn while no popup covers the pane, since sixel output would be
+// painted over the popup.
+func (m *Model) renderPosterPane(width int, height int, showImage bool) string {
+	innerW, innerH := width-4, height-2
+	content := []string{}
+	if item, ok := m.currentBrowseSelection(); ok {
+		content = append(content, lipgloss.NewStyle().Foreground(accentText).Bold(true).Render(compactText(item.Name, innerW)))
+		if showImage {
+			if lines, ok := m.posterBox(item.Poster, innerW, min(innerH-2, posterRows(innerW))); ok {
+				content = append(content, "")
+				content = append(content, lines...)
+			}
+		}
+	}
+
+	style := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(footerBorder).
+		Padding(0, 1).
+		Width(width - 2).
+		Height(height - 2)
+
+	return style.Render(strings.Join(content, "\n"))
+}
+
+// posterRows is how many rows a poster cols cells wide takes up, for the
+// usual 2:3 poster in cells about twice as high as they are wide.
+func posterRows(cols int) int {
+	return cols * 3 / 4
+}
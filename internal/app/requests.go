@@ -0,0 +1,69 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+type requestKind int
+
+const (
+	requestSearch requestKind = iota
+	requestMeta
+	requestEpisodes
+	requestStreams
+	requestCache
+	requestResolve
+	requestLogin
+	requestLibrary
+	requestAccount
+	requestPoster
+	requestCatalog
+	requestMoviePage
+	requestShowPage
+	requestKinds
+)
+
+var requestTimeouts = [requestKinds]time.Duration{
+	requestSearch:    20 * time.Second,
+	requestMeta:      20 * time.Second,
+	requestEpisodes:  20 * time.Second,
+	requestStreams:   30 * time.Second,
+	requestCache:     20 * time.Second,
+	requestResolve:   120 * time.Second,
+	requestLogin:     15 * time.Minute,
+	requestLibrary:   60 * time.Second,
+	requestAccount:   20 * time.Second,
+	requestPoster:    30 * time.Second,
+	requestCatalog:   20 * time.Second,
+	requestMoviePage: 20 * time.Second,
+	requestShowPage:  20 * time.Second,
+}
+
+// requests holds the cancel func of the latest in-flight request of each
+// kind, so navigating away can abort work whose results would be dropped.
+type requests [requestKinds]context.CancelFunc
+
+// start cancels the previous request of kind and returns the context for a
+// new one.
+func (r *requests) start(kind requestKind) context.Context {
+	r.cancel(kind)
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeouts[kind])
+	r[kind] = cancel
+	return ctx
+}
+
+func (r *requests) cancel(kinds ...requestKind) {
+	for _, kind := range kinds {
+		if r[kind] != nil {
+			r[kind]()
+			r[kind] = nil
+		}
+	}
+}
+
+// canceled reports whether err comes from a request the model cancelled.
+func canceled(err error) bool {
+	return errors.Is(err, context.Canceled)
+}
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// updateSearchInput passes msg to the search input. Changing the query drops
+// the search in flight and schedules a new one once typing pauses.
+func (m Model) updateSearchInput(msg tea.Msg) (tea.Model, tea.Cmd) {
+	previous := strings.TrimSpace(m.input.Value())
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	query := strings.TrimSpace(m.input.Value())
+	if query == previous {
+		return m, cmd
+	}
+
+	m.cancelSearch()
+	return m, tea.Batch(cmd, searchDebounceCmd(query))
+}
+
+// debouncedSearch runs the search for query unless the input changed since,
+// or its results are already shown or on their way.
+func (m Model) debouncedSearch(query string) (tea.Model, tea.Cmd) {
+	if m.mode != modeBrowse || query != strings.TrimSpace(m.input.Value()) {
+		return m, nil
+	}
+	if query == "" {
+		if m.browse == browseSearch {
+			m.clearSearch()
+		}
+		return m, nil
+	}
+	if query == m.searching || (m.browse == browseSearch && query == m.searchQuery) {
+		return m, nil
+	}
+	return m, m.startSearch(query)
+}
+
+// submitSearch handles Enter in the search input: it moves to the results,
+// running the search first unless they are already shown.
+func (m Model) submitSearch() (tea.Model, tea.Cmd) {
+	query := strings.TrimSpace(m.input.Value())
+	if query == "" {
+		m.clearSearch()
+		return m, nil
+	}
+	if m.searching == "" && m.browse == browseSearch && query == m.searchQuery {
+		m.focusSearchResults()
+		return m, nil
+	}
+
+	m.searchSubmitted = true
+	if query == m.searching {
+		return m, nil
+	}
+	return m, m.startSearch(query)
+}
+
+// startSearch searches for query, cancelling any older search still running.
+func (m *Model) startSearch(query string) tea.Cmd {
+	m.searching = query
+	m.status = "Searching..."
+	return loadSearchCmd(m.requests.start(requestSearch), m.client, query)
+}
+
+// cancelSearch drops the search in flight, leaving the shown results as they
+// are.
+func (m *Model) cancelSearch() {
+	m.requests.cancel(requestSearch)
+	m.searching = ""
+	m.searchSubmitted = false
+}
+
+func (m *Model) clearSearch() {
+	m.cancelSearch()
+	m.browse = browsePopular
+	m.searchMovieResults = nil
+	m.searchShowResults = nil
+	m.syncBrowsePanes()
+	m.status = "Search cleared"
+}
+
+func (m *Model) focusSearchResults() {
+	if len(m.searchMovieResults) == 0 && len(m.searchShowResults) > 0 {
+		m.setFocus(focusRight)
+		return
+	}
+	m.setFocus(focusMovies)
+}
@@ -0,0 +1,233 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"tuiflix/internal/api"
+)
+
+type settingField struct {
+	label   string
+	choices []string
+	get     func(api.TorrentioOptions) string
+	set     func(*api.TorrentioOptions, string) error
+}
+
+var torrentioSettings = []settingField{
+	{
+		label: "Providers",
+		get:   func(o api.TorrentioOptions) string { return strings.Join(o.Providers, ",") },
+		set: func(o *api.TorrentioOptions, value string) error {
+			o.Providers = splitList(value)
+			return nil
+		},
+	},
+	{
+		label:   "Sort",
+		choices: api.TorrentioSorts,
+		get: func(o api.TorrentioOptions) string {
+			if o.Sort == "" {
+				return api.TorrentioSorts[0]
+			}
+			return o.Sort
+		},
+		set: func(o *api.TorrentioOptions, value string) error {
+			o.Sort = value
+			return nil
+		},
+	},
+	{
+		label: "Exclude qualities",
+		get:   func(o api.TorrentioOptions) string { return strings.Join(o.QualityFilter, ",") },
+		set: func(o *api.TorrentioOptions, value string) error {
+			o.QualityFilter = splitList(value)
+			return nil
+		},
+	},
+	{
+		label: "Language priority",
+		get:   func(o api.TorrentioOptions) string { return strings.Join(o.Languages, ",") },
+		set: func(o *api.TorrentioOptions, value string) error {
+			o.Languages = splitList(value)
+			return nil
+		},
+	},
+	{
+		label: "Max results per quality",
+		get: func(o api.TorrentioOptions) string {
+			if o.Limit <= 0 {
+				return ""
+			}
+			return strconv.Itoa(o.Limit)
+		},
+		set: func(o *api.TorrentioOptions, value string) error {
+			if strings.TrimSpace(value) == "" {
+				o.Limit = 0
+				return nil
+			}
+			limit, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || limit < 0 {
+				return fmt.Errorf("limit must be a positive number")
+			}
+			o.Limit = limit
+			return nil
+		},
+	},
+	{
+		label:   "Send debrid key",
+		choices: []string{"off", "on"},
+		get: func(o api.TorrentioOptions) string {
+			if o.DebridKey {
+				return "on"
+			}
+			return "off"
+		},
+		set: func(o *api.TorrentioOptions, value string) error {
+			o.DebridKey = value == "on"
+			return nil
+		},
+	},
+}
+
+func (m Model) openSettings() (tea.Model, tea.Cmd) {
+	m.mode = modeSettings
+	m.settingsCursor = 0
+	m.settingsEditing = false
+	m.setFocus(focusSettings)
+	m.status = "Torrentio settings: Enter edits, Esc saves and closes"
+	return m, nil
+}
+
+func (m Model) updateSettingsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	field := torrentioSettings[m.settingsCursor]
+
+	if m.settingsEditing {
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			m.settingsEditing = false
+			m.settingsInput.Blur()
+			m.status = "Edit cancelled"
+			return m, nil
+		case "enter":
+			opts := m.config.Torrentio
+			if err := field.set(&opts, m.settingsInput.Value()); err != nil {
+				m.status = err.Error()
+				return m, nil
+			}
+			m.config.Torrentio = opts
+			m.settingsEditing = false
+			m.settingsInput.Blur()
+			m.status = field.label + " updated"
+			return m, nil
+		}
+
+		var cmd tea.Cmd
+		m.settingsInput, cmd = m.settingsInput.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "up", "k":
+		m.settingsCursor = (m.settingsCursor + len(torrentioSettings) - 1) % len(torrentioSettings)
+	case "down", "j", "tab":
+		m.settingsCursor = (m.settingsCursor + 1) % len(torrentioSettings)
+	case "enter", " ":
+		if len(field.choices) > 0 {
+			current := indexOfString(field.choices, field.get(m.config.Torrentio))
+			next := field.choices[(current+1)%len(field.choices)]
+			opts := m.config.Torrentio
+			_ = field.set(&opts, next)
+			m.config.Torrentio = opts
+			m.status = field.label + ": " + next
+			return m, nil
+		}
+		m.settingsEditing = true
+		m.settingsInput.SetValue(field.get(m.config.Torrentio))
+		m.settingsInput.CursorEnd()
+		m.settingsInput.Focus()
+		m.status = "Comma separated values, Enter to apply, Esc to cancel"
+	case "esc":
+		m.client.SetTorrentioOptions(m.config.Torrentio)
+		m.mode = modeBrowse
+		m.setFocus(focusRight)
+		m.status = "Settings saved"
+		return m, saveConfigCmd(m.config)
+	}
+	return m, nil
+}
+
+func (m *Model) renderSettingsPopup(width int, height int) string {
+	popupW := min(width-6, 80)
+	if popupW < 52 {
+		popupW = width - 2
+	}
+	popupH := min(height-4, len(torrentioSettings)+8)
+
+	labelStyle := lipgloss.NewStyle().Foreground(mutedText).Width(26)
+	valueStyle := lipgloss.NewStyle().Foreground(statusColor)
+	selectedStyle := lipgloss.NewStyle().Foreground(accentText).Bold(true)
+
+	rows := make([]string, 0, len(torrentioSettings))
+	for idx, field := range torrentioSettings {
+		value := field.get(m.config.Torrentio)
+		if value == "" {
+			value = "(default)"
+		}
+		if idx == m.settingsCursor && m.settingsEditing {
+			value = m.settingsInput.View()
+		}
+
+		label := labelStyle.Render(field.label)
+		if idx == m.settingsCursor {
+			rows = append(rows, selectedStyle.Render("> ")+label+selectedStyle.Render(value))
+			continue
+		}
+		rows = append(rows, "  "+label+valueStyle.Render(value))
+	}
+
+	content := []string{
+		lipgloss.NewStyle().Foreground(accentText).Bold(true).Render("Torrentio Settings"),
+		lipgloss.NewStyle().Foreground(mutedText).Render("Up/Down to move, Enter to edit or cycle, Esc to save"),
+		"",
+		strings.Join(rows, "\n"),
+	}
+
+	style := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(accentText).
+		Padding(0, 1).
+		Width(popupW).
+		Height(popupH)
+
+	return style.Render(strings.Join(content, "\n"))
+}
+
+func splitList(value string) []string {
+	parts := strings.Split(value, ",")
+	out := make([]string, 0, len(parts))
+	for _, part := range parts {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+func indexOfString(values []string, target string) int {
+	for idx, value := range values {
+		if value == target {
+			return idx
+		}
+	}
+	return 0
+}
@@ -0,0 +1,99 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"tuiflix/internal/api"
+)
+
+type streamSort int
+
+const (
+	sortAddonOrder streamSort = iota
+	sortSize
+	sortSeeders
+	sortResolution
+	sortCached
+)
+
+var streamSortNames = []string{"addon order", "size", "seeders", "resolution", "cached first"}
+
+const gigabyte = int64(1) << 30
+
+var maxSizeSteps = []int64{0, 2 * gigabyte, 5 * gigabyte, 10 * gigabyte, 20 * gigabyte, 40 * gigabyte}
+
+type streamFilter struct {
+	sort       streamSort
+	hide4K     bool
+	hideCam    bool
+	maxSize    int64
+	cachedOnly bool
+}
+
+func (f streamFilter) apply(streams []api.Stream) []api.Stream {
+	out := make([]api.Stream, 0, len(streams))
+	for _, stream := range streams {
+		info := stream.Info
+		if f.hide4K && info.ResolutionRank() >= 2160 {
+			continue
+		}
+		if f.hideCam && (info.Source == "CAM" || info.Source == "TS" || info.Source == "SCR") {
+			continue
+		}
+		if f.maxSize > 0 && info.Size > f.maxSize {
+			continue
+		}
+		if f.cachedOnly && !stream.Cached {
+			continue
+		}
+		out = append(out, stream)
+	}
+
+	switch f.sort {
+	case sortSize:
+		sort.SliceStable(out, func(i, j int) bool { return out[i].Info.Size > out[j].Info.Size })
+	case sortSeeders:
+		sort.SliceStable(out, func(i, j int) bool { return out[i].Info.Seeders > out[j].Info.Seeders })
+	case sortResolution:
+		sort.SliceStable(out, func(i, j int) bool {
+			return out[i].Info.ResolutionRank() > out[j].Info.ResolutionRank()
+		})
+	case sortCached:
+		sort.SliceStable(out, func(i, j int) bool {
+			if out[i].Cached != out[j].Cached {
+				return out[i].Cached
+			}
+			return out[i].Info.ResolutionRank() > out[j].Info.ResolutionRank()
+		})
+	}
+
+	return out
+}
+
+func (f streamFilter) nextMaxSize() int64 {
+	for idx, step := range maxSizeSteps {
+		if step == f.maxSize {
+			return maxSizeSteps[(idx+1)%len(maxSizeSteps)]
+		}
+	}
+	return 0
+}
+
+func (f streamFilter) describe() string {
+	parts := []string{"sort: " + streamSortNames[f.sort]}
+	if f.hide4K {
+		parts = append(parts, "no 4K")
+	}
+	if f.hideCam {
+		parts = append(parts, "no CAM/TS")
+	}
+	if f.maxSize > 0 {
+		parts = append(parts, fmt.Sprintf("max %d GB", f.maxSize/gigabyte))
+	}
+	if f.cachedOnly {
+		parts = append(parts, "cached only")
+	}
+	return strings.Join(parts, " | ")
+}
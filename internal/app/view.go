@@ -5,6 +5,10 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"tuiflix/internal/api"
+	"tuiflix/internal/app/components"
 )
 
 var (
@@ -12,6 +16,7 @@ var (
 	statusColor  = lipgloss.Color("252")
 	mutedText    = lipgloss.Color("245")
 	accentText   = lipgloss.Color("39")
+	warningText  = lipgloss.Color("214")
 )
 
 func (m Model) View() string {
@@ -23,36 +28,57 @@ func (m Model) View() string {
 		return "Terminal too small for tuiflix"
 	}
 
-	topHeight := m.height - 4
-	leftWidth := m.width / 2
-	rightWidth := m.width - leftWidth
+	footer := m.renderFooter()
+	topHeight := m.height - lipgloss.Height(footer)
 
-	top := m.renderBrowseTop(topHeight, leftWidth, rightWidth)
-	if m.mode == modeDetail {
+	top := m.renderBrowseTop(topHeight, m.width)
+	if m.mode != modeBrowse {
 		top = m.renderPopupOverlay(top, m.width, topHeight)
 	}
 
-	return lipgloss.JoinVertical(lipgloss.Left, top, m.renderFooter())
+	return lipgloss.JoinVertical(lipgloss.Left, top, footer)
 }
 
-func (m *Model) renderBrowseTop(height int, leftWidth int, rightWidth int) string {
+func (m *Model) renderBrowseTop(height int, width int) string {
+	posterWidth := 0
+	if m.listPosters() {
+		posterWidth = min(width/4, 40)
+	}
+	leftWidth := (width - posterWidth) / 2
+	rightWidth := width - posterWidth - leftWidth
+
 	left := m.movies.View(leftWidth, height, m.focus == focusMovies)
 	right := m.right.View(rightWidth, height, m.focus == focusRight)
-	return lipgloss.JoinHorizontal(lipgloss.Top, left, right)
+	if posterWidth == 0 {
+		return lipgloss.JoinHorizontal(lipgloss.Top, left, right)
+	}
+	posterPane := m.renderPosterPane(posterWidth, height, m.mode == modeBrowse)
+	return lipgloss.JoinHorizontal(lipgloss.Top, left, right, posterPane)
 }
 
 func (m *Model) renderPopupOverlay(base string, width int, height int) string {
-	overlay := lipgloss.Place(
-		width,
-		height,
-		lipgloss.Center,
-		lipgloss.Center,
-		m.renderPopup(width, height),
-	)
-	return mergeOverlay(base, overlay)
+	return overlayCenter(base, m.renderPopup(width, height), width, height)
 }
 
 func (m *Model) renderPopup(width int, height int) string {
+	if m.mode == modeSettings {
+		return m.renderSettingsPopup(width, height)
+	}
+	if m.mode == modeLogin {
+		return m.renderLoginPopup(width, height)
+	}
+	if m.mode == modeAccount {
+		return m.renderAccountPopup(width, height)
+	}
+	if m.mode == modeLibrary {
+		return m.renderLibraryPopup(width, height)
+	}
+	if m.mode == modeCatalog {
+		return m.renderCatalogPopup(width, height)
+	}
+	if m.popup == popupInfo {
+		return m.renderInfoPopup(width, height)
+	}
 	if m.popup == popupSeasonEpisode {
 		return m.renderSeasonEpisodePopup(width, height)
 	}
@@ -60,18 +86,19 @@ func (m *Model) renderPopup(width int, height int) string {
 }
 
 func (m *Model) renderSeasonEpisodePopup(width int, height int) string {
-	popupW := min(width-6, 88)
+	popupW := min(width-6, 96)
 	if popupW < 52 {
 		popupW = width - 2
 	}
-	popupH := min(height-4, 22)
+	popupH := min(height-4, 24)
 	if popupH < 14 {
 		popupH = 14
 	}
 
-	listsHeight := popupH - 6
-	leftW := (popupW - 5) / 2
-	rightW := popupW - 5 - leftW
+	const overviewLines = 3
+	listsHeight := popupH - 4 - overviewLines
+	leftW := 16
+	rightW := popupW - 2 - leftW
 
 	pickers := lipgloss.JoinHorizontal(
 		lipgloss.Top,
@@ -85,6 +112,9 @@ func (m *Model) renderSeasonEpisodePopup(width int, height int) string {
 		lipgloss.NewStyle().Foreground(mutedText).Render("Tab/Left/Right to switch, arrows to move, Enter to continue"),
 		pickers,
 	}
+	if episode, ok := m.episodes.Selected(); ok && episode.Overview != "" {
+		content = append(content, wrapText(episode.Overview, popupW-4, overviewLines)...)
+	}
 
 	style := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
@@ -106,22 +136,36 @@ func (m *Model) renderStreamsPopup(width int, height int) string {
 		popupH = 14
 	}
 
-	listHeight := popupH - 6
+	listHeight := popupH - 5
 	contextLine := ""
 	if m.selected.Type == "series" {
 		contextLine = fmt.Sprintf("S%02dE%02d", m.currentSeason(), m.currentEpisode())
 	}
 
-	instructions := "Enter to open, Esc to close"
+	instructions := "Enter to open, Esc to close, s sort, 4/c/m/d filter"
 	if m.selected.Type == "series" {
-		instructions = "Enter to open, Esc to go back"
+		instructions = "Enter to open, Esc to go back, s sort, 4/c/m/d filter"
+	}
+
+	if m.resolving != nil {
+		instructions = "Resolving with " + m.client.DebridName() + ", Esc to cancel"
+	}
+
+	instructionsLine := lipgloss.NewStyle().Foreground(mutedText).Render(instructions)
+	if m.fallback != nil {
+		prompt := fmt.Sprintf("%s failed. Open the raw link in %s without debrid? y/n", m.client.DebridName(), m.player.Name())
+		instructionsLine = lipgloss.NewStyle().Foreground(warningText).Bold(true).Render(compactText(prompt, popupW-4))
 	}
 
 	content := []string{
 		lipgloss.NewStyle().Foreground(accentText).Bold(true).Render("Choose Stream"),
 		lipgloss.NewStyle().Foreground(mutedText).Render(compactText(m.selected.Name+" "+contextLine, popupW-4)),
-		lipgloss.NewStyle().Foreground(mutedText).Render(instructions),
-		m.streams.View(popupW-4, listHeight, m.focus == focusStreams),
+		instructionsLine,
+		lipgloss.NewStyle().Foreground(accentText).Render(compactText(m.streamFilter.describe(), popupW-4)),
+		m.streams.View(popupW-2, listHeight, m.focus == focusStreams),
+	}
+	if m.resolving != nil {
+		content[3] = lipgloss.NewStyle().Foreground(accentText).Render(renderResolveProgress(m.resolving.progress, popupW-4))
 	}
 
 	style := lipgloss.NewStyle().
@@ -134,45 +178,28 @@ func (m *Model) renderStreamsPopup(width int, height int) string {
 	return style.Render(strings.Join(content, "\n"))
 }
 
-func mergeOverlay(base string, overlay string) string {
+// overlayCenter draws popup over the middle of base. Both are styled, so
+// lines are cut by display width with their escape sequences kept intact.
+func overlayCenter(base string, popup string, width int, height int) string {
 	baseLines := strings.Split(base, "\n")
-	overlayLines := strings.Split(overlay, "\n")
-	lineCount := max(len(baseLines), len(overlayLines))
-	out := make([]string, lineCount)
-
-	for i := 0; i < lineCount; i++ {
-		var baseRunes []rune
-		var overlayRunes []rune
-
-		if i < len(baseLines) {
-			baseRunes = []rune(baseLines[i])
-		}
-		if i < len(overlayLines) {
-			overlayRunes = []rune(overlayLines[i])
+	popupLines := strings.Split(popup, "\n")
+	popupW := lipgloss.Width(popup)
+	x := max((width-popupW)/2, 0)
+	y := max((height-len(popupLines))/2, 0)
+
+	for i, line := range popupLines {
+		row := y + i
+		if row >= len(baseLines) {
+			break
 		}
-
-		width := max(len(baseRunes), len(overlayRunes))
-		if width == 0 {
-			out[i] = ""
-			continue
-		}
-
-		merged := make([]rune, width)
-		for j := 0; j < width; j++ {
-			ch := ' '
-			if j < len(baseRunes) {
-				ch = baseRunes[j]
-			}
-			if j < len(overlayRunes) && overlayRunes[j] != ' ' {
-				ch = overlayRunes[j]
-			}
-			merged[j] = ch
-		}
-
-		out[i] = string(merged)
+		under := baseLines[row]
+		left := ansi.Truncate(under, x, "")
+		left += strings.Repeat(" ", x-ansi.StringWidth(left))
+		line += strings.Repeat(" ", popupW-ansi.StringWidth(line))
+		baseLines[row] = left + ansi.ResetStyle + line + ansi.ResetStyle + ansi.TruncateLeft(under, x+popupW, "")
 	}
 
-	return strings.Join(out, "\n")
+	return strings.Join(baseLines, "\n")
 }
 
 func min(a int, b int) int {
@@ -195,11 +222,19 @@ func (m *Model) renderFooter() string {
 	helpModel := m.help
 	helpModel.Width = m.width - 4
 
+	status := lipgloss.NewStyle().Foreground(statusColor).Render(m.status)
+	if m.accountWarning != "" {
+		status = lipgloss.NewStyle().Foreground(warningText).Bold(true).Render(m.accountWarning) + "  " + status
+	}
+
 	lines := []string{
 		searchLabel + "  " + m.input.View(),
-		lipgloss.NewStyle().Foreground(statusColor).Render(m.status),
-		lipgloss.NewStyle().Foreground(mutedText).Render(helpModel.View(m.keys)),
+		status,
+	}
+	if m.playback != nil {
+		lines = append(lines, m.renderNowPlaying(m.width-2))
 	}
+	lines = append(lines, lipgloss.NewStyle().Foreground(mutedText).Render(helpModel.View(m.keys)))
 
 	box := lipgloss.NewStyle().
 		BorderTop(true).
@@ -209,3 +244,75 @@ func (m *Model) renderFooter() string {
 
 	return box.Render(strings.Join(lines, "\n"))
 }
+
+func (m *Model) renderNowPlaying(width int) string {
+	state := m.playbackState
+	icon := "▶"
+	if state.Paused {
+		icon = "⏸"
+	}
+
+	clock := formatClock(state.Position)
+	if state.Duration > 0 {
+		clock += " / " + formatClock(state.Duration)
+	}
+
+	title := compactText(m.nowPlaying.title(), max(10, width/3))
+	barWidth := width - len([]rune(title)) - len(clock) - 8
+	bar := ""
+	if state.Duration > 0 && barWidth >= 10 {
+		bar = "  " + renderBar(state.Position/state.Duration, barWidth)
+	}
+
+	return lipgloss.NewStyle().Foreground(accentText).Bold(true).Render(icon+" "+title) +
+		lipgloss.NewStyle().Foreground(mutedText).Render(bar+"  "+clock)
+}
+
+func renderResolveProgress(progress api.ResolveProgress, width int) string {
+	status := strings.ReplaceAll(progress.Status, "_", " ")
+	if status == "" {
+		status = "resolving"
+	}
+
+	details := []string{}
+	if progress.Percent > 0 {
+		details = append(details, fmt.Sprintf("%.0f%%", progress.Percent))
+	}
+	if progress.Speed > 0 {
+		details = append(details, components.FormatBytes(progress.Speed)+"/s")
+	}
+	if progress.Seeders > 0 {
+		details = append(details, fmt.Sprintf("%d seeders", progress.Seeders))
+	}
+
+	label := fmt.Sprintf("%-18s", status)
+	suffix := strings.Join(details, " · ")
+	barWidth := width - lipgloss.Width(label) - lipgloss.Width(suffix) - 2
+	if barWidth < 10 {
+		return compactText(strings.TrimSpace(label+" "+suffix), width)
+	}
+	return label + renderBar(progress.Percent/100, barWidth) + "  " + suffix
+}
+
+func renderBar(fraction float64, width int) string {
+	if fraction < 0 {
+		fraction = 0
+	}
+	if fraction > 1 {
+		fraction = 1
+	}
+	filled := int(fraction * float64(width))
+	return strings.Repeat("━", filled) + strings.Repeat("─", width-filled)
+}
+
+func formatClock(seconds float64) string {
+	total := int(seconds)
+	if total < 0 {
+		total = 0
+	}
+	h, m, s := total/3600, (total/60)%60, total%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%02d:%02d", m, s)
+}
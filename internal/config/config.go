@@ -0,0 +1,181 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"tuiflix/internal/api"
+	"tuiflix/internal/fsutil"
+)
+
+const appDir = "tuiflix"
+
+type Config struct {
+	Player    PlayerConfig         `json:"player"`
+	Addons    []string             `json:"addons,omitempty"`
+	Torrentio api.TorrentioOptions `json:"torrentio"`
+	Debrid    DebridConfig         `json:"debrid"`
+	Posters   PosterConfig         `json:"posters"`
+	// AllowFallback offers to open the raw magnet or link when debrid
+	// resolution fails, after an explicit confirmation.
+	AllowFallback bool `json:"allow_fallback,omitempty"`
+
+	// filePlayer keeps the on-disk player settings so environment
+	// overrides are not written back by Save.
+	filePlayer PlayerConfig
+	// loadErr is why the config file could not be read. Save refuses to
+	// overwrite such a file with the defaults.
+	loadErr error
+}
+
+type PlayerConfig struct {
+	Backend string `json:"backend,omitempty"`
+	Command string `json:"command,omitempty"`
+}
+
+// PosterConfig controls poster images. Protocol is auto, kitty, sixel,
+// blocks or off; InList also shows the selected title's poster beside the
+// browse lists.
+type PosterConfig struct {
+	Protocol string `json:"protocol,omitempty"`
+	InList   bool   `json:"in_list,omitempty"`
+}
+
+type DebridConfig struct {
+	Provider string `json:"provider,omitempty"`
+	APIKey   string `json:"api_key,omitempty"`
+}
+
+// DebridEnvVars maps each debrid provider to the environment variable that
+// holds its API key.
+var DebridEnvVars = map[string]string{
+	api.DebridRealDebrid: "REALDEBRID",
+	api.DebridAllDebrid:  "ALLDEBRID",
+	api.DebridPremiumize: "PREMIUMIZE",
+	api.DebridTorBox:     "TORBOX",
+}
+
+// ResolveDebrid picks the debrid provider and key: the configured provider
+// wins, otherwise the first provider whose environment variable is set.
+func (c Config) ResolveDebrid() (string, string) {
+	provider := api.CanonicalDebrid(c.Debrid.Provider)
+	if provider != "" {
+		key := strings.TrimSpace(c.Debrid.APIKey)
+		if key == "" {
+			key = strings.TrimSpace(os.Getenv(DebridEnvVars[provider]))
+		}
+		return provider, key
+	}
+
+	for _, candidate := range api.DebridProviders {
+		if key := strings.TrimSpace(os.Getenv(DebridEnvVars[candidate])); key != "" {
+			return candidate, key
+		}
+	}
+	return api.DebridRealDebrid, ""
+}
+
+func Default() Config {
+	return Config{
+		Player: PlayerConfig{Backend: "auto"},
+	}
+}
+
+func Dir() (string, error) {
+	if base := strings.TrimSpace(os.Getenv("XDG_CONFIG_HOME")); base != "" {
+		return filepath.Join(base, appDir), nil
+	}
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, appDir), nil
+}
+
+func DataDir() (string, error) {
+	if base := strings.TrimSpace(os.Getenv("XDG_DATA_HOME")); base != "" {
+		return filepath.Join(base, appDir), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share", appDir), nil
+}
+
+func CacheDir() (string, error) {
+	if base := strings.TrimSpace(os.Getenv("XDG_CACHE_HOME")); base != "" {
+		return filepath.Join(base, appDir), nil
+	}
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, appDir), nil
+}
+
+func Path() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.json"), nil
+}
+
+// Load reads config.json. Environment overrides apply even when the file
+// is missing or broken, in which case the defaults are used.
+func Load() (Config, error) {
+	cfg := Default()
+	err := cfg.load()
+	cfg.applyEnv()
+	cfg.loadErr = err
+	return cfg, err
+}
+
+func (c *Config) load() error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, c); err != nil {
+		*c = Default()
+		return err
+	}
+	return nil
+}
+
+func (c Config) Save() error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if c.loadErr != nil {
+		return fmt.Errorf("not overwriting %s, it could not be read: %w", path, c.loadErr)
+	}
+	c.Player = c.filePlayer
+	return fsutil.WriteJSON(path, c)
+}
+
+func (c *Config) applyEnv() {
+	c.filePlayer = c.Player
+	if backend := strings.TrimSpace(os.Getenv("TUIFLIX_PLAYER")); backend != "" {
+		c.Player.Backend = backend
+	}
+	if command := strings.TrimSpace(os.Getenv("TUIFLIX_PLAYER_COMMAND")); command != "" {
+		c.Player.Command = command
+	}
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"tuiflix/internal/api"
+	"tuiflix/internal/fsutil"
+)
+
+func realDebridLoginPath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "realdebrid_token.json"), nil
+}
+
+// LoadRealDebridLogin returns the token stored by the device-flow login, or
+// nil when the user never logged in.
+func LoadRealDebridLogin() (*api.OAuthToken, error) {
+	path, err := realDebridLoginPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var token api.OAuthToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func SaveRealDebridLogin(token api.OAuthToken) error {
+	path, err := realDebridLoginPath()
+	if err != nil {
+		return err
+	}
+	return fsutil.WriteJSON(path, token)
+}
+
+// ClientConfig builds the api.Config for c. A stored Real-Debrid login is
+// used when Real-Debrid is the provider and no API key is set.
+func (c Config) ClientConfig() api.Config {
+	provider, key := c.ResolveDebrid()
+	cfg := api.Config{
+		DebridProvider: provider,
+		DebridToken:    key,
+		Addons:         c.Addons,
+		Torrentio:      c.Torrentio,
+	}
+
+	if provider == api.DebridRealDebrid && key == "" {
+		if login, err := LoadRealDebridLogin(); err == nil && login != nil {
+			cfg.RealDebridLogin = login
+			cfg.OnLoginRefresh = func(token api.OAuthToken) {
+				_ = SaveRealDebridLogin(token)
+			}
+		}
+	}
+	return cfg
+}
@@ -0,0 +1,32 @@
+// Package fsutil holds the file helpers shared by the packages that keep
+// state on disk.
+package fsutil
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// WriteFile writes data to path through a temporary file, so a crash never
+// leaves a half-written file behind. Missing directories are created.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// WriteJSON saves value as indented JSON readable only by the user, since
+// the files can hold tokens.
+func WriteJSON(path string, value any) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteFile(path, append(data, '\n'), 0o600)
+}
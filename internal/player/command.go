@@ -0,0 +1,45 @@
+package player
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// commandPlayer runs a user-defined template such as "celluloid {url}".
+// {url}, {title} and {start} are substituted per argument; without {url} the target
+// is appended as the last argument.
+type commandPlayer struct {
+	args []string
+}
+
+func newCommandPlayer(template string) (Player, error) {
+	args := strings.Fields(template)
+	if len(args) == 0 {
+		return nil, errors.New("player command template is empty")
+	}
+	return commandPlayer{args: args}, nil
+}
+
+func (c commandPlayer) Name() string {
+	return filepath.Base(c.args[0])
+}
+
+func (c commandPlayer) Open(target string, opts Options) error {
+	args := make([]string, 0, len(c.args)+1)
+	hasTarget := false
+	for _, arg := range c.args[1:] {
+		if strings.Contains(arg, "{url}") {
+			hasTarget = true
+		}
+		arg = strings.ReplaceAll(arg, "{url}", target)
+		arg = strings.ReplaceAll(arg, "{title}", opts.Title)
+		arg = strings.ReplaceAll(arg, "{start}", formatSeconds(opts.Start))
+		args = append(args, arg)
+	}
+	if !hasTarget {
+		args = append(args, target)
+	}
+	return start(exec.Command(c.args[0], args...))
+}
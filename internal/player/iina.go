@@ -1,7 +1,39 @@
 package player
 
-import "os/exec"
+import (
+	"os"
+	"os/exec"
+	"runtime"
+)
 
-func OpenIINA(target string) error {
-	return exec.Command("open", "-a", "IINA", target).Start()
+type iinaPlayer struct{}
+
+func (iinaPlayer) Name() string {
+	return "IINA"
+}
+
+func (iinaPlayer) Open(target string, opts Options) error {
+	if hasBinary("iina") {
+		args := []string{}
+		if opts.Title != "" {
+			args = append(args, "--mpv-force-media-title="+opts.Title)
+		}
+		if opts.Start > 0 {
+			args = append(args, "--mpv-start="+formatSeconds(opts.Start))
+		}
+		args = append(args, target)
+		return start(exec.Command("iina", args...))
+	}
+	return start(exec.Command("open", "-a", "IINA", target))
+}
+
+func iinaInstalled() bool {
+	if runtime.GOOS != "darwin" {
+		return false
+	}
+	if hasBinary("iina") {
+		return true
+	}
+	_, err := os.Stat("/Applications/IINA.app")
+	return err == nil
 }
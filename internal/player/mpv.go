@@ -0,0 +1,21 @@
+package player
+
+import "os/exec"
+
+type mpvPlayer struct{}
+
+func (mpvPlayer) Name() string {
+	return "mpv"
+}
+
+func (mpvPlayer) Open(target string, opts Options) error {
+	args := []string{"--force-window=immediate"}
+	if opts.Title != "" {
+		args = append(args, "--force-media-title="+opts.Title)
+	}
+	if opts.Start > 0 {
+		args = append(args, "--start="+formatSeconds(opts.Start))
+	}
+	args = append(args, target)
+	return start(exec.Command("mpv", args...))
+}
@@ -0,0 +1,196 @@
+package player
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	propTimePos = iota + 1
+	propDuration
+	propPause
+)
+
+const stateThrottle = 500 * time.Millisecond
+
+type State struct {
+	Position   float64
+	Duration   float64
+	Paused     bool
+	Exited     bool
+	ExitReason string
+}
+
+// Controllable is implemented by backends that can report playback state
+// and accept commands while the player is running.
+type Controllable interface {
+	Player
+	Launch(target string, opts Options) (*Controller, error)
+}
+
+type Controller struct {
+	cmd     *exec.Cmd
+	conn    net.Conn
+	socket  string
+	updates chan State
+
+	writeMu sync.Mutex
+}
+
+func (mpvPlayer) Launch(target string, opts Options) (*Controller, error) {
+	socket := filepath.Join(os.TempDir(), fmt.Sprintf("tuiflix-mpv-%d-%d.sock", os.Getpid(), time.Now().UnixNano()))
+
+	args := []string{"--force-window=immediate", "--input-ipc-server=" + socket}
+	if opts.Title != "" {
+		args = append(args, "--force-media-title="+opts.Title)
+	}
+	if opts.Start > 0 {
+		args = append(args, "--start="+formatSeconds(opts.Start))
+	}
+	args = append(args, target)
+
+	cmd := exec.Command("mpv", args...)
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	conn, err := dialIPC(socket, 5*time.Second)
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		_ = os.Remove(socket)
+		return nil, fmt.Errorf("mpv ipc: %w", err)
+	}
+
+	c := &Controller{
+		cmd:     cmd,
+		conn:    conn,
+		socket:  socket,
+		updates: make(chan State, 16),
+	}
+
+	for id, name := range map[int]string{propTimePos: "time-pos", propDuration: "duration", propPause: "pause"} {
+		if err := c.send("observe_property", id, name); err != nil {
+			_ = conn.Close()
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait()
+			_ = os.Remove(socket)
+			return nil, fmt.Errorf("mpv ipc: %w", err)
+		}
+	}
+
+	go c.run()
+	return c, nil
+}
+
+func (c *Controller) Updates() <-chan State {
+	return c.updates
+}
+
+func (c *Controller) TogglePause() error {
+	return c.send("cycle", "pause")
+}
+
+func (c *Controller) Seek(seconds float64) error {
+	return c.send("seek", seconds, "relative")
+}
+
+func (c *Controller) Stop() error {
+	return c.send("quit")
+}
+
+func (c *Controller) send(args ...any) error {
+	payload, err := json.Marshal(map[string]any{"command": args})
+	if err != nil {
+		return err
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	_ = c.conn.SetWriteDeadline(time.Now().Add(2 * time.Second))
+	_, err = c.conn.Write(append(payload, '\n'))
+	return err
+}
+
+func (c *Controller) run() {
+	var (
+		state    State
+		lastSent time.Time
+	)
+
+	scanner := bufio.NewScanner(c.conn)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event struct {
+			Event  string          `json:"event"`
+			ID     int             `json:"id"`
+			Data   json.RawMessage `json:"data"`
+			Reason string          `json:"reason"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil || event.Event == "" {
+			continue
+		}
+
+		switch event.Event {
+		case "property-change":
+			switch event.ID {
+			case propTimePos:
+				_ = json.Unmarshal(event.Data, &state.Position)
+				if time.Since(lastSent) < stateThrottle {
+					continue
+				}
+			case propDuration:
+				_ = json.Unmarshal(event.Data, &state.Duration)
+			case propPause:
+				_ = json.Unmarshal(event.Data, &state.Paused)
+			default:
+				continue
+			}
+		case "end-file":
+			state.ExitReason = event.Reason
+			continue
+		default:
+			continue
+		}
+
+		lastSent = time.Now()
+		c.updates <- state
+	}
+
+	_ = c.conn.Close()
+	waitErr := c.cmd.Wait()
+	_ = os.Remove(c.socket)
+
+	state.Exited = true
+	if state.ExitReason == "" {
+		state.ExitReason = "quit"
+		if waitErr != nil {
+			state.ExitReason = waitErr.Error()
+		}
+	}
+	c.updates <- state
+	close(c.updates)
+}
+
+func dialIPC(socket string, timeout time.Duration) (net.Conn, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		conn, err := net.Dial("unix", socket)
+		if err == nil {
+			return conn, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, errors.New("socket did not become available")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
@@ -0,0 +1,80 @@
+package player
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+type Options struct {
+	Title string
+	Start float64
+}
+
+func formatSeconds(seconds float64) string {
+	return strconv.FormatFloat(seconds, 'f', 0, 64)
+}
+
+type Player interface {
+	Name() string
+	Open(target string, opts Options) error
+}
+
+func New(backend string, command string) (Player, error) {
+	switch strings.ToLower(strings.TrimSpace(backend)) {
+	case "", "auto":
+		if strings.TrimSpace(command) != "" {
+			return newCommandPlayer(command)
+		}
+		return Detect()
+	case "mpv":
+		return mpvPlayer{}, nil
+	case "vlc":
+		return vlcPlayer{}, nil
+	case "iina":
+		return iinaPlayer{}, nil
+	case "xdg-open", "open", "system":
+		return systemPlayer{}, nil
+	case "command", "custom":
+		return newCommandPlayer(command)
+	default:
+		return nil, fmt.Errorf("unknown player backend: %s", backend)
+	}
+}
+
+func Detect() (Player, error) {
+	if hasBinary("mpv") {
+		return mpvPlayer{}, nil
+	}
+	if iinaInstalled() {
+		return iinaPlayer{}, nil
+	}
+	if hasBinary("vlc") {
+		return vlcPlayer{}, nil
+	}
+	if runtime.GOOS == "darwin" && hasBinary("open") {
+		return systemPlayer{}, nil
+	}
+	if hasBinary("xdg-open") {
+		return systemPlayer{}, nil
+	}
+	return nil, errors.New("no supported player found in PATH (tried mpv, iina, vlc, xdg-open)")
+}
+
+func hasBinary(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
+
+func start(cmd *exec.Cmd) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go func() {
+		_ = cmd.Wait()
+	}()
+	return nil
+}
@@ -0,0 +1,22 @@
+package player
+
+import (
+	"os/exec"
+	"runtime"
+)
+
+type systemPlayer struct{}
+
+func (systemPlayer) Name() string {
+	if runtime.GOOS == "darwin" {
+		return "default app"
+	}
+	return "xdg-open"
+}
+
+func (systemPlayer) Open(target string, _ Options) error {
+	if runtime.GOOS == "darwin" {
+		return start(exec.Command("open", target))
+	}
+	return start(exec.Command("xdg-open", target))
+}
@@ -0,0 +1,21 @@
+package player
+
+import "os/exec"
+
+type vlcPlayer struct{}
+
+func (vlcPlayer) Name() string {
+	return "VLC"
+}
+
+func (vlcPlayer) Open(target string, opts Options) error {
+	args := []string{}
+	if opts.Title != "" {
+		args = append(args, "--meta-title="+opts.Title)
+	}
+	if opts.Start > 0 {
+		args = append(args, "--start-time="+formatSeconds(opts.Start))
+	}
+	args = append(args, target)
+	return start(exec.Command("vlc", args...))
+}
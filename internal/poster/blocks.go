@@ -0,0 +1,34 @@
+package poster
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+)
+
+// halfBlocks draws img, which is cols x rows*2 pixels, with one upper half
+// block per cell: the foreground colours the top pixel and the background the
+// bottom one.
+func halfBlocks(img *image.RGBA, cols int, rows int) []string {
+	lines := make([]string, 0, rows)
+	for y := 0; y < rows; y++ {
+		var b strings.Builder
+		var lastTop, lastBottom color.RGBA
+		for x := 0; x < cols; x++ {
+			top := img.RGBAAt(x, y*2)
+			bottom := img.RGBAAt(x, y*2+1)
+			if x == 0 || top != lastTop {
+				fmt.Fprintf(&b, "\x1b[38;2;%d;%d;%dm", top.R, top.G, top.B)
+			}
+			if x == 0 || bottom != lastBottom {
+				fmt.Fprintf(&b, "\x1b[48;2;%d;%d;%dm", bottom.R, bottom.G, bottom.B)
+			}
+			b.WriteString("▀")
+			lastTop, lastBottom = top, bottom
+		}
+		b.WriteString("\x1b[0m")
+		lines = append(lines, b.String())
+	}
+	return lines
+}
@@ -0,0 +1,193 @@
+package poster
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"tuiflix/internal/fsutil"
+)
+
+const (
+	// maxDownload bounds a single poster download.
+	maxDownload = 8 << 20
+	// maxAge is how long a poster stays in the disk cache after it was
+	// last written.
+	maxAge = 30 * 24 * time.Hour
+	// memoryEntries is how many decoded posters are kept in memory.
+	memoryEntries = 64
+)
+
+// Cache downloads posters, keeps the files on disk and the decoded images of
+// the most recently used ones in memory. It is safe for concurrent use.
+type Cache struct {
+	dir  string
+	http *http.Client
+
+	mu     sync.Mutex
+	images map[string]image.Image
+	order  []string
+	failed map[string]bool
+}
+
+// NewCache stores posters under dir. An empty dir keeps them in memory only.
+// Files older than maxAge are removed in the background.
+func NewCache(dir string) *Cache {
+	c := &Cache{
+		dir:    dir,
+		http:   &http.Client{Timeout: 20 * time.Second},
+		images: map[string]image.Image{},
+		failed: map[string]bool{},
+	}
+	if dir != "" {
+		go c.prune(time.Now())
+	}
+	return c
+}
+
+// Image returns the decoded poster at url if it has been fetched already.
+func (c *Cache) Image(url string) (image.Image, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	img, ok := c.images[url]
+	return img, ok
+}
+
+// Wanted reports whether url still needs fetching: it is neither loaded nor
+// known to fail.
+func (c *Cache) Wanted(url string) bool {
+	if url == "" {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, loaded := c.images[url]
+	return !loaded && !c.failed[url]
+}
+
+// Fetch loads the poster at url from memory, disk or the network, in that
+// order. Failures other than cancellation are remembered so the same broken
+// poster is not requested again.
+func (c *Cache) Fetch(ctx context.Context, url string) (image.Image, error) {
+	if img, ok := c.Image(url); ok {
+		return img, nil
+	}
+
+	img, err := c.load(ctx, url)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			c.failed[url] = true
+		}
+		return nil, err
+	}
+	c.remember(url, img)
+	return img, nil
+}
+
+func (c *Cache) load(ctx context.Context, url string) (image.Image, error) {
+	path := c.path(url)
+	if path != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			if img, err := decode(data); err == nil {
+				return img, nil
+			}
+		}
+	}
+
+	data, err := c.download(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	img, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+	if path != "" {
+		_ = fsutil.WriteFile(path, data, 0o644)
+	}
+	return img, nil
+}
+
+func (c *Cache) download(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("poster download failed: %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDownload+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxDownload {
+		return nil, errors.New("poster is too large")
+	}
+	return data, nil
+}
+
+// remember adds img to the memory cache, evicting the oldest entry when it is
+// full. The caller holds c.mu.
+func (c *Cache) remember(url string, img image.Image) {
+	if _, ok := c.images[url]; !ok {
+		c.order = append(c.order, url)
+	}
+	c.images[url] = img
+	for len(c.order) > memoryEntries {
+		delete(c.images, c.order[0])
+		c.order = c.order[1:]
+	}
+}
+
+func (c *Cache) path(url string) string {
+	if c.dir == "" {
+		return ""
+	}
+	sum := sha1.Sum([]byte(url))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:]))
+}
+
+func (c *Cache) prune(now time.Time) {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() {
+			continue
+		}
+		if now.Sub(info.ModTime()) > maxAge {
+			_ = os.Remove(filepath.Join(c.dir, entry.Name()))
+		}
+	}
+}
+
+func decode(data []byte) (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode poster: %w", err)
+	}
+	return img, nil
+}
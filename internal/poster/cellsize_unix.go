@@ -0,0 +1,19 @@
+//go:build unix
+
+package poster
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// terminalCellSize reads the size of one cell in pixels from the terminal
+// attached to stdout. Not every terminal reports it.
+func terminalCellSize() (int, int, bool) {
+	ws, err := unix.IoctlGetWinsize(int(os.Stdout.Fd()), unix.TIOCGWINSZ)
+	if err != nil || ws.Col == 0 || ws.Row == 0 || ws.Xpixel == 0 || ws.Ypixel == 0 {
+		return 0, 0, false
+	}
+	return int(ws.Xpixel / ws.Col), int(ws.Ypixel / ws.Row), true
+}
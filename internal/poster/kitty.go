@@ -0,0 +1,96 @@
+package poster
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/png"
+	"strings"
+)
+
+// kittyChunk is the largest payload the kitty graphics protocol accepts in
+// one escape sequence.
+const kittyChunk = 4096
+
+// placeholder is the kitty Unicode placeholder. Cells holding it show part of
+// the image whose id is encoded in the cell's foreground colour, so the
+// poster moves and disappears with the text around it.
+const placeholder = "\U0010EEEE"
+
+// diacritics encode the row and column of a placeholder cell, in the order
+// kitty defines them.
+var diacritics = []rune{
+	0x0305, 0x030D, 0x030E, 0x0310, 0x0312, 0x033D, 0x033E, 0x033F,
+	0x0346, 0x034A, 0x034B, 0x034C, 0x0350, 0x0351, 0x0352, 0x0357,
+	0x035B, 0x0363, 0x0364, 0x0365, 0x0366, 0x0367, 0x0368, 0x0369,
+	0x036A, 0x036B, 0x036C, 0x036D, 0x036E, 0x036F, 0x0483, 0x0484,
+	0x0485, 0x0486, 0x0487, 0x0592, 0x0593, 0x0594, 0x0595, 0x0597,
+	0x0598, 0x0599, 0x059C, 0x059D, 0x059E, 0x059F, 0x05A0, 0x05A1,
+	0x05A8, 0x05A9, 0x05AB, 0x05AC, 0x05AF, 0x05C4, 0x0610, 0x0611,
+	0x0612, 0x0613, 0x0614, 0x0615, 0x0616, 0x0617, 0x0657, 0x0658,
+	0x0659, 0x065A, 0x065B, 0x065D, 0x065E, 0x06D6, 0x06D7, 0x06D8,
+	0x06D9, 0x06DA, 0x06DB, 0x06DC, 0x06DF, 0x06E0, 0x06E1, 0x06E2,
+	0x06E4, 0x06E7, 0x06E8, 0x06EB, 0x06EC,
+}
+
+// kittyID derives a stable 24-bit image id, so it fits in a true colour
+// foreground.
+func kittyID(key frameKey) uint32 {
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%s|%d|%d", key.url, key.cols, key.rows)
+	id := h.Sum32() & 0xFFFFFF
+	if id == 0 {
+		id = 1
+	}
+	return id
+}
+
+// kittyTransmit sends img as PNG and creates a virtual placement of cols x
+// rows cells for the placeholders to refer to. q=2 keeps the terminal from
+// answering on stdin.
+func kittyTransmit(img image.Image, id uint32, cols int, rows int) string {
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, img); err != nil {
+		return ""
+	}
+	payload := base64.StdEncoding.EncodeToString(encoded.Bytes())
+
+	var b strings.Builder
+	for first := true; first || payload != ""; first = false {
+		chunk := payload[:min(len(payload), kittyChunk)]
+		payload = payload[len(chunk):]
+		more := 0
+		if payload != "" {
+			more = 1
+		}
+		if first {
+			fmt.Fprintf(&b, "\x1b_Ga=T,U=1,f=100,q=2,i=%d,c=%d,r=%d,m=%d;%s\x1b\\", id, cols, rows, more, chunk)
+		} else {
+			fmt.Fprintf(&b, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+	}
+	return b.String()
+}
+
+func kittyPlaceholders(id uint32, cols int, rows int) []string {
+	color := fmt.Sprintf("\x1b[38;2;%d;%d;%dm", id>>16&0xFF, id>>8&0xFF, id&0xFF)
+	lines := make([]string, 0, rows)
+	for y := 0; y < rows; y++ {
+		var b strings.Builder
+		b.WriteString(color)
+		for x := 0; x < cols; x++ {
+			b.WriteString(placeholder)
+			b.WriteRune(diacritics[y])
+			b.WriteRune(diacritics[x])
+		}
+		b.WriteString("\x1b[39m")
+		lines = append(lines, b.String())
+	}
+	return lines
+}
+
+func kittyDelete(id uint32) string {
+	return fmt.Sprintf("\x1b_Ga=d,d=I,q=2,i=%d\x1b\\", id)
+}
@@ -0,0 +1,229 @@
+package poster
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Protocol is how posters are drawn in the terminal.
+type Protocol int
+
+const (
+	ProtocolOff Protocol = iota
+	ProtocolBlocks
+	ProtocolSixel
+	ProtocolKitty
+)
+
+var protocolNames = []string{"off", "blocks", "sixel", "kitty"}
+
+func (p Protocol) String() string {
+	return protocolNames[p]
+}
+
+// ParseProtocol maps a config value to a protocol. An empty value or "auto"
+// picks one for the current terminal with DetectProtocol.
+func ParseProtocol(value string, getenv func(string) string) (Protocol, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" || value == "auto" {
+		return DetectProtocol(getenv), nil
+	}
+	for idx, name := range protocolNames {
+		if name == value {
+			return Protocol(idx), nil
+		}
+	}
+	return ProtocolOff, fmt.Errorf("unknown poster protocol %q (use auto, kitty, sixel, blocks or off)", value)
+}
+
+// DetectProtocol guesses the best protocol from the environment. Terminal
+// multiplexers do not pass graphics through, so they get half blocks.
+func DetectProtocol(getenv func(string) string) Protocol {
+	term := getenv("TERM")
+	program := getenv("TERM_PROGRAM")
+
+	switch {
+	case term == "" || term == "dumb" || term == "linux":
+		return ProtocolOff
+	case getenv("TMUX") != "" || strings.HasPrefix(term, "screen") || strings.HasPrefix(term, "tmux"):
+		return ProtocolBlocks
+	case getenv("KITTY_WINDOW_ID") != "" || term == "xterm-kitty" || term == "xterm-ghostty" || program == "ghostty":
+		return ProtocolKitty
+	case program == "WezTerm" || program == "iTerm.app" || program == "mintty":
+		return ProtocolSixel
+	case strings.HasPrefix(term, "foot") || strings.Contains(term, "mlterm") || strings.Contains(term, "contour") || strings.Contains(term, "sixel"):
+		return ProtocolSixel
+	}
+	return ProtocolBlocks
+}
+
+const (
+	// transmitWindow is how long a kitty image keeps being sent with every
+	// frame, so that at least one frame carrying it reaches the terminal.
+	transmitWindow = 250 * time.Millisecond
+	// maxFrames bounds the rendered posters kept between frames.
+	maxFrames = 128
+)
+
+type frameKey struct {
+	url  string
+	cols int
+	rows int
+}
+
+// frame is a poster rendered for one box. transmit is only set for kitty and
+// has to precede the lines until the terminal holds the image.
+type frame struct {
+	lines    []string
+	id       uint32
+	transmit string
+}
+
+// Renderer turns cached posters into text for a box of terminal cells. It
+// remembers what it drew, so calling Render on every frame is cheap. It is
+// safe for concurrent use.
+type Renderer struct {
+	protocol Protocol
+	cache    *Cache
+	cellW    int
+	cellH    int
+
+	mu     sync.Mutex
+	frames map[frameKey]frame
+	sent   map[uint32]time.Time
+}
+
+func NewRenderer(protocol Protocol, cache *Cache) *Renderer {
+	cellW, cellH, ok := terminalCellSize()
+	if !ok {
+		cellW, cellH = 10, 20
+	}
+	return &Renderer{
+		protocol: protocol,
+		cache:    cache,
+		cellW:    cellW,
+		cellH:    cellH,
+		frames:   map[frameKey]frame{},
+		sent:     map[uint32]time.Time{},
+	}
+}
+
+func (r *Renderer) Enabled() bool {
+	return r != nil && r.protocol != ProtocolOff
+}
+
+func (r *Renderer) Protocol() Protocol {
+	return r.protocol
+}
+
+func (r *Renderer) Cache() *Cache {
+	return r.cache
+}
+
+// Render draws the poster at url centred in a box of cols x rows cells and
+// returns one string per row, each exactly cols cells wide. ok is false while
+// the poster has not been fetched.
+func (r *Renderer) Render(url string, cols int, rows int) ([]string, bool) {
+	if !r.Enabled() || url == "" || cols <= 0 || rows <= 0 {
+		return nil, false
+	}
+	img, ok := r.cache.Image(url)
+	if !ok {
+		return nil, false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := frameKey{url: url, cols: cols, rows: rows}
+	f, ok := r.frames[key]
+	if !ok {
+		f = r.draw(img, key)
+		if len(r.frames) >= maxFrames {
+			r.frames = map[frameKey]frame{}
+		}
+		r.frames[key] = f
+	}
+
+	if f.transmit == "" {
+		return f.lines, true
+	}
+	first, sent := r.sent[f.id]
+	if !sent {
+		first = time.Now()
+		r.sent[f.id] = first
+	}
+	if time.Since(first) > transmitWindow {
+		return f.lines, true
+	}
+
+	lines := append([]string(nil), f.lines...)
+	lines[0] = f.transmit + lines[0]
+	return lines, true
+}
+
+// Close asks the terminal to drop the images it was sent.
+func (r *Renderer) Close(w io.Writer) error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id := range r.sent {
+		if _, err := io.WriteString(w, kittyDelete(id)); err != nil {
+			return err
+		}
+	}
+	r.sent = map[uint32]time.Time{}
+	return nil
+}
+
+func (r *Renderer) draw(img image.Image, key frameKey) frame {
+	bounds := img.Bounds()
+	pixelW, pixelH := fit(bounds.Dx(), bounds.Dy(), key.cols*r.cellW, key.rows*r.cellH)
+	cols := clampCells((pixelW+r.cellW/2)/r.cellW, key.cols)
+	rows := clampCells((pixelH+r.cellH/2)/r.cellH, key.rows)
+
+	var f frame
+	switch r.protocol {
+	case ProtocolKitty:
+		cols, rows = min(cols, len(diacritics)), min(rows, len(diacritics))
+		f.id = kittyID(key)
+		f.transmit = kittyTransmit(Scale(img, cols*r.cellW, rows*r.cellH), f.id, cols, rows)
+		f.lines = kittyPlaceholders(f.id, cols, rows)
+	case ProtocolSixel:
+		f.lines = sixelRows(Scale(img, cols*r.cellW, rows*r.cellH), cols, rows, r.cellH)
+	default:
+		f.lines = halfBlocks(Scale(img, cols, rows*2), cols, rows)
+	}
+	f.lines = center(f.lines, cols, key.cols, key.rows)
+	return f
+}
+
+// center pads lines of width cols into a box of boxCols x boxRows cells.
+func center(lines []string, cols int, boxCols int, boxRows int) []string {
+	left := strings.Repeat(" ", (boxCols-cols)/2)
+	right := strings.Repeat(" ", boxCols-cols-len(left))
+	blank := strings.Repeat(" ", boxCols)
+
+	top := (boxRows - len(lines)) / 2
+	out := make([]string, 0, boxRows)
+	for len(out) < top {
+		out = append(out, blank)
+	}
+	for _, line := range lines {
+		out = append(out, left+line+right)
+	}
+	for len(out) < boxRows {
+		out = append(out, blank)
+	}
+	return out
+}
+
+func clampCells(value int, limit int) int {
+	return min(max(value, 1), limit)
+}
@@ -0,0 +1,89 @@
+package poster
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestDetectProtocol(t *testing.T) {
+	cases := []struct {
+		env  map[string]string
+		want Protocol
+	}{
+		{env: map[string]string{"TERM": "xterm-kitty"}, want: ProtocolKitty},
+		{env: map[string]string{"TERM": "xterm-256color", "TERM_PROGRAM": "ghostty"}, want: ProtocolKitty},
+		{env: map[string]string{"TERM": "xterm-256color", "TERM_PROGRAM": "WezTerm"}, want: ProtocolSixel},
+		{env: map[string]string{"TERM": "foot"}, want: ProtocolSixel},
+		{env: map[string]string{"TERM": "xterm-kitty", "TMUX": "/tmp/tmux-1000/default,1,0"}, want: ProtocolBlocks},
+		{env: map[string]string{"TERM": "xterm-256color"}, want: ProtocolBlocks},
+		{env: map[string]string{"TERM": "dumb"}, want: ProtocolOff},
+	}
+	for _, tc := range cases {
+		getenv := func(key string) string { return tc.env[key] }
+		if got := DetectProtocol(getenv); got != tc.want {
+			t.Errorf("DetectProtocol(%v) = %s, want %s", tc.env, got, tc.want)
+		}
+	}
+
+	if _, err := ParseProtocol("ascii", func(string) string { return "" }); err == nil {
+		t.Error("ParseProtocol accepted an unknown protocol")
+	}
+}
+
+func TestRenderPoster(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 40, 60))
+	for y := 0; y < 60; y++ {
+		for x := 0; x < 40; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 6), G: uint8(y * 4), B: 90, A: 255})
+		}
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = png.Encode(w, img)
+	}))
+	defer server.Close()
+
+	cache := NewCache(t.TempDir())
+	url := server.URL + "/poster.png"
+	if _, err := cache.Fetch(context.Background(), url); err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if cache.Wanted(url) {
+		t.Fatal("fetched poster is still wanted")
+	}
+
+	for _, protocol := range []Protocol{ProtocolBlocks, ProtocolSixel, ProtocolKitty} {
+		renderer := NewRenderer(protocol, cache)
+		lines, ok := renderer.Render(url, 16, 10)
+		if !ok || len(lines) != 10 {
+			t.Fatalf("%s: got %d lines, ok=%v", protocol, len(lines), ok)
+		}
+		for idx, line := range lines {
+			if width := ansi.StringWidth(line); width != 16 {
+				t.Fatalf("%s: line %d is %d cells wide", protocol, idx, width)
+			}
+		}
+		joined := strings.Join(lines, "\n")
+		switch protocol {
+		case ProtocolKitty:
+			if !strings.Contains(joined, "\x1b_Ga=T,U=1") || !strings.Contains(joined, placeholder) {
+				t.Fatalf("kitty output lacks the transmission or placeholders")
+			}
+		case ProtocolSixel:
+			if !strings.Contains(joined, "\x1bP0;1;0q") {
+				t.Fatalf("sixel output lacks the sixel image")
+			}
+		}
+	}
+
+	if _, ok := NewRenderer(ProtocolBlocks, cache).Render(server.URL+"/missing.png", 16, 10); ok {
+		t.Fatal("rendered a poster that was never fetched")
+	}
+}
@@ -0,0 +1,60 @@
+package poster
+
+import (
+	"image"
+	"image/color"
+)
+
+// Scale resizes img to width x height pixels. Each target pixel averages the
+// source pixels it covers, which keeps downscaled posters smooth; upscaling
+// falls back to the nearest source pixel.
+func Scale(img image.Image, width int, height int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, max(width, 1), max(height, 1)))
+	src := img.Bounds()
+	if src.Empty() {
+		return dst
+	}
+
+	for y := 0; y < dst.Rect.Dy(); y++ {
+		y0 := src.Min.Y + y*src.Dy()/dst.Rect.Dy()
+		y1 := max(src.Min.Y+(y+1)*src.Dy()/dst.Rect.Dy(), y0+1)
+		for x := 0; x < dst.Rect.Dx(); x++ {
+			x0 := src.Min.X + x*src.Dx()/dst.Rect.Dx()
+			x1 := max(src.Min.X+(x+1)*src.Dx()/dst.Rect.Dx(), x0+1)
+			dst.SetRGBA(x, y, average(img, x0, y0, x1, y1))
+		}
+	}
+	return dst
+}
+
+func average(img image.Image, x0 int, y0 int, x1 int, y1 int) color.RGBA {
+	var r, g, b, a, n uint64
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			cr, cg, cb, ca := img.At(x, y).RGBA()
+			r += uint64(cr)
+			g += uint64(cg)
+			b += uint64(cb)
+			a += uint64(ca)
+			n++
+		}
+	}
+	return color.RGBA{
+		R: uint8(r / n >> 8),
+		G: uint8(g / n >> 8),
+		B: uint8(b / n >> 8),
+		A: uint8(a / n >> 8),
+	}
+}
+
+// fit returns the largest width x height that keeps the aspect ratio of
+// imgW x imgH inside boxW x boxH.
+func fit(imgW int, imgH int, boxW int, boxH int) (int, int) {
+	if imgW <= 0 || imgH <= 0 || boxW <= 0 || boxH <= 0 {
+		return 0, 0
+	}
+	if imgW*boxH > imgH*boxW {
+		return boxW, max(imgH*boxW/imgW, 1)
+	}
+	return max(imgW*boxH/imgH, 1), boxH
+}
@@ -0,0 +1,101 @@
+package poster
+
+import (
+	"fmt"
+	"image"
+	"strings"
+)
+
+// sixelRows draws img, which is cols cells wide and rows cells of cellH
+// pixels high, as one sixel image per row of cells. Every row is drawn
+// after the spaces under it and restores the cursor afterwards, so each line
+// stays self-contained when the TUI repaints only some of them.
+func sixelRows(img *image.RGBA, cols int, rows int, cellH int) []string {
+	lines := make([]string, 0, rows)
+	for y := 0; y < rows; y++ {
+		slice := img.SubImage(image.Rect(0, y*cellH, img.Rect.Dx(), (y+1)*cellH)).(*image.RGBA)
+		lines = append(lines, fmt.Sprintf("%s\x1b[%dD\x1b7%s\x1b8\x1b[%dC", strings.Repeat(" ", cols), cols, encodeSixel(slice), cols))
+	}
+	return lines
+}
+
+// encodeSixel encodes img with a 6x6x6 colour cube. Pixels no colour covers
+// stay transparent, so a slice never paints below its own row.
+func encodeSixel(img *image.RGBA) string {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\x1bP0;1;0q\"1;1;%d;%d", width, height)
+
+	indexes := make([]int, width*height)
+	defined := map[int]bool{}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := img.RGBAAt(bounds.Min.X+x, bounds.Min.Y+y)
+			r, g, bl := (int(c.R)*5+127)/255, (int(c.G)*5+127)/255, (int(c.B)*5+127)/255
+			idx := r*36 + g*6 + bl
+			indexes[y*width+x] = idx
+			if !defined[idx] {
+				defined[idx] = true
+				fmt.Fprintf(&b, "#%d;2;%d;%d;%d", idx, r*20, g*20, bl*20)
+			}
+		}
+	}
+
+	for top := 0; top < height; top += 6 {
+		if top > 0 {
+			b.WriteByte('-')
+		}
+		var order []int
+		bits := map[int][]byte{}
+		for dy := 0; dy < 6 && top+dy < height; dy++ {
+			for x := 0; x < width; x++ {
+				idx := indexes[(top+dy)*width+x]
+				row, ok := bits[idx]
+				if !ok {
+					row = make([]byte, width)
+					bits[idx] = row
+					order = append(order, idx)
+				}
+				row[x] |= 1 << dy
+			}
+		}
+
+		for n, idx := range order {
+			if n > 0 {
+				b.WriteByte('$')
+			}
+			fmt.Fprintf(&b, "#%d", idx)
+			writeSixelRun(&b, bits[idx])
+		}
+	}
+
+	b.WriteString("\x1b\\")
+	return b.String()
+}
+
+// writeSixelRun writes one colour of a band, run-length encoding repeats.
+// Empty columns at the end are left out.
+func writeSixelRun(b *strings.Builder, row []byte) {
+	end := len(row)
+	for end > 0 && row[end-1] == 0 {
+		end--
+	}
+	row = row[:end]
+	for x := 0; x < len(row); {
+		run := 1
+		for x+run < len(row) && row[x+run] == row[x] {
+			run++
+		}
+		char := byte(63 + row[x])
+		if run > 3 {
+			fmt.Fprintf(b, "!%d%c", run, char)
+		} else {
+			for i := 0; i < run; i++ {
+				b.WriteByte(char)
+			}
+		}
+		x += run
+	}
+}
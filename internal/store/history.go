@@ -0,0 +1,148 @@
+package store
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"tuiflix/internal/api"
+)
+
+const (
+	finishedFraction = 0.92
+	minResumeSeconds = 30
+)
+
+type StreamRef struct {
+	Name     string `json:"name,omitempty"`
+	Title    string `json:"title,omitempty"`
+	URL      string `json:"url,omitempty"`
+	InfoHash string `json:"info_hash,omitempty"`
+	FileIdx  *int   `json:"file_idx,omitempty"`
+}
+
+type HistoryEntry struct {
+	Item      api.MediaItem `json:"item"`
+	Season    int           `json:"season,omitempty"`
+	Episode   int           `json:"episode,omitempty"`
+	Stream    StreamRef     `json:"stream"`
+	Position  float64       `json:"position"`
+	Duration  float64       `json:"duration"`
+	Finished  bool          `json:"finished"`
+	WatchedAt time.Time     `json:"watched_at"`
+}
+
+// ResumeAt returns the offset playback should start from, or 0 when the
+// entry was finished or barely started.
+func (e HistoryEntry) ResumeAt() float64 {
+	if e.Finished || e.Position < minResumeSeconds {
+		return 0
+	}
+	return e.Position
+}
+
+type History struct {
+	path string
+
+	mu      sync.Mutex
+	entries []HistoryEntry
+}
+
+func openHistory(path string) (*History, error) {
+	h := &History{path: path}
+	if err := readJSON(path, &h.entries); err != nil {
+		// Keep a broken file around for inspection instead of overwriting it.
+		return &History{}, err
+	}
+	return h, nil
+}
+
+func StreamRefFrom(stream api.Stream) StreamRef {
+	return StreamRef{
+		Name:     stream.Name,
+		Title:    stream.Title,
+		URL:      stream.URL,
+		InfoHash: stream.InfoHash,
+		FileIdx:  stream.FileIdx,
+	}
+}
+
+func (h *History) Record(item api.MediaItem, season int, episode int, stream api.Stream) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	idx := h.indexLocked(item.ID, season, episode)
+	if idx < 0 {
+		h.entries = append(h.entries, HistoryEntry{Item: item, Season: season, Episode: episode})
+		idx = len(h.entries) - 1
+	}
+
+	entry := &h.entries[idx]
+	entry.Item = item
+	entry.Stream = StreamRefFrom(stream)
+	entry.WatchedAt = time.Now()
+	if entry.Finished {
+		entry.Finished = false
+		entry.Position = 0
+	}
+}
+
+func (h *History) UpdatePosition(itemID string, season int, episode int, position float64, duration float64, ended bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	idx := h.indexLocked(itemID, season, episode)
+	if idx < 0 {
+		return
+	}
+
+	entry := &h.entries[idx]
+	if position > 0 {
+		entry.Position = position
+	}
+	if duration > 0 {
+		entry.Duration = duration
+	}
+	entry.WatchedAt = time.Now()
+	if ended || (entry.Duration > 0 && entry.Position/entry.Duration >= finishedFraction) {
+		entry.Finished = true
+	}
+}
+
+func (h *History) Lookup(itemID string, season int, episode int) (HistoryEntry, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	idx := h.indexLocked(itemID, season, episode)
+	if idx < 0 {
+		return HistoryEntry{}, false
+	}
+	return h.entries[idx], true
+}
+
+// Entries returns a copy of the history, most recently watched first.
+func (h *History) Entries() []HistoryEntry {
+	h.mu.Lock()
+	entries := append([]HistoryEntry(nil), h.entries...)
+	h.mu.Unlock()
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].WatchedAt.After(entries[j].WatchedAt)
+	})
+	return entries
+}
+
+func (h *History) Save() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return writeJSON(h.path, h.entries)
+}
+
+func (h *History) indexLocked(itemID string, season int, episode int) int {
+	for idx, entry := range h.entries {
+		if entry.Item.ID == itemID && entry.Season == season && entry.Episode == episode {
+			return idx
+		}
+	}
+	return -1
+}
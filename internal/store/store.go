@@ -0,0 +1,53 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"tuiflix/internal/config"
+	"tuiflix/internal/fsutil"
+)
+
+type Store struct {
+	History   *History
+	Watchlist *Watchlist
+}
+
+func Open() (*Store, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return Empty(), err
+	}
+
+	history, historyErr := openHistory(filepath.Join(dir, "history.json"))
+	watchlist, watchlistErr := openWatchlist(filepath.Join(dir, "watchlist.json"))
+
+	return &Store{History: history, Watchlist: watchlist}, errors.Join(historyErr, watchlistErr)
+}
+
+// Empty returns an in-memory store that never touches the disk.
+func Empty() *Store {
+	return &Store{History: &History{}, Watchlist: &Watchlist{}}
+}
+
+func readJSON(path string, out any) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// writeJSON saves value to path. Stores without a path stay in memory.
+func writeJSON(path string, value any) error {
+	if path == "" {
+		return nil
+	}
+	return fsutil.WriteJSON(path, value)
+}
@@ -0,0 +1,75 @@
+package store
+
+import (
+	"sync"
+	"time"
+
+	"tuiflix/internal/api"
+)
+
+type WatchlistEntry struct {
+	Item    api.MediaItem `json:"item"`
+	AddedAt time.Time     `json:"added_at"`
+}
+
+type Watchlist struct {
+	path string
+
+	mu      sync.Mutex
+	entries []WatchlistEntry
+}
+
+func openWatchlist(path string) (*Watchlist, error) {
+	w := &Watchlist{path: path}
+	if err := readJSON(path, &w.entries); err != nil {
+		return &Watchlist{}, err
+	}
+	return w, nil
+}
+
+// Toggle adds item to the watchlist, or removes it when already present.
+// It reports whether the item is on the watchlist afterwards.
+func (w *Watchlist) Toggle(item api.MediaItem) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for idx, entry := range w.entries {
+		if entry.Item.ID == item.ID {
+			w.entries = append(w.entries[:idx], w.entries[idx+1:]...)
+			return false
+		}
+	}
+
+	w.entries = append(w.entries, WatchlistEntry{Item: item, AddedAt: time.Now()})
+	return true
+}
+
+// IDs returns the set of item IDs on the watchlist.
+func (w *Watchlist) IDs() map[string]bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ids := make(map[string]bool, len(w.entries))
+	for _, entry := range w.entries {
+		ids[entry.Item.ID] = true
+	}
+	return ids
+}
+
+// Items returns the watchlist, most recently added first.
+func (w *Watchlist) Items() []api.MediaItem {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	items := make([]api.MediaItem, 0, len(w.entries))
+	for idx := len(w.entries) - 1; idx >= 0; idx-- {
+		items = append(items, w.entries[idx].Item)
+	}
+	return items
+}
+
+func (w *Watchlist) Save() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return writeJSON(w.path, w.entries)
+}